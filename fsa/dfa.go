@@ -0,0 +1,120 @@
+package fsa
+
+// Finite state automata over a finite alphabet of symbols 0, 1, ..., symbols-1
+// For words in a group presentation, the alphabet is the set of signed generators x_0, x_0^-1, x_1, x_1^-1, ...
+// which are the symbols 0, 1, 2, 3, ... (see Symbol and Letter), but any finite alphabet works, e.g. the padded pairs used by multiplier automata
+
+// The number of symbols of the alphabet of signed generators of a presentation with the given number of generators
+func GeneratorSymbols(generators int) int { return 2 * generators }
+
+// The symbol of a letter (generator, ±1): x_i is 2i and x_i^-1 is 2i+1
+func Symbol(x [2]int) int {
+	if x[1] < 0 {
+		return 2*x[0] + 1
+	}
+	return 2 * x[0]
+}
+
+// The letter (generator, ±1) of a symbol, the inverse of Symbol
+func Letter(a int) [2]int {
+	if a%2 == 1 {
+		return [2]int{a / 2, -1}
+	}
+	return [2]int{a / 2, 1}
+}
+
+// The symbol of the inverse letter
+func InverseSymbol(a int) int { return a ^ 1 }
+
+// Expands a word given as (generator, exponent) syllables into symbols
+func Symbols(w [][2]int) []int {
+	symbols := make([]int, 0, len(w))
+	for _, u := range w {
+		a := Symbol(u)
+		for range max(u[1], -u[1]) {
+			symbols = append(symbols, a)
+		}
+	}
+	return symbols
+}
+
+// Writes a word of symbols as (generator, exponent) syllables, merging adjacent equal letters (no free reduction)
+func Letters(symbols []int) [][2]int {
+	w := make([][2]int, 0, len(symbols))
+	for _, a := range symbols {
+		x := Letter(a)
+		if n := len(w); n > 0 && w[n-1][0] == x[0] && (w[n-1][1] > 0) == (x[1] > 0) {
+			w[n-1][1] += x[1]
+		} else {
+			w = append(w, x)
+		}
+	}
+	return w
+}
+
+// A deterministic finite state automaton, possibly partial: missing transitions lead to rejection
+// States are 0, 1, ..., NumStates()-1
+type DFA struct {
+	symbols int
+	start   int
+	accept  []bool
+	trans   [][]int //trans[s][a] is the state reached from s by reading a, -1 if none
+}
+
+// Constructor for a DFA over symbols symbols, with a single non-accepting start state 0 and no transitions
+func NewDFA(symbols int) *DFA {
+	d := &DFA{symbols: symbols}
+	d.AddState(false)
+	return d
+}
+
+// adds a state without transitions and returns it
+func (d *DFA) AddState(accept bool) int {
+	row := make([]int, d.symbols)
+	for a := range row {
+		row[a] = -1
+	}
+	d.trans = append(d.trans, row)
+	d.accept = append(d.accept, accept)
+	return len(d.accept) - 1
+}
+
+func (d *DFA) SetStart(s int)               { d.start = s }
+func (d *DFA) SetAccept(s int, accept bool) { d.accept[s] = accept }
+func (d *DFA) SetTransition(s, a, t int)    { d.trans[s][a] = t } //use t = -1 to remove the transition
+func (d *DFA) Start() int                   { return d.start }
+func (d *DFA) IsAccept(s int) bool          { return d.accept[s] }
+func (d *DFA) NumStates() int               { return len(d.accept) }
+func (d *DFA) NumSymbols() int              { return d.symbols }
+func (d *DFA) Next(s, a int) int            { return d.trans[s][a] } //-1 if there is no transition
+
+// Runs d on word from state s, returning the state reached or -1 if some transition was missing
+func (d *DFA) Run(s int, word []int) int {
+	for _, a := range word {
+		if s < 0 {
+			return -1
+		}
+		s = d.trans[s][a]
+	}
+	return s
+}
+
+// checks whether d accepts the word of symbols
+func (d *DFA) Accepts(word []int) bool {
+	s := d.Run(d.start, word)
+	return s >= 0 && d.accept[s]
+}
+
+// checks whether d accepts the word given as (generator, exponent) syllables, see Symbols
+func (d *DFA) AcceptsWord(w [][2]int) bool {
+	return d.Accepts(Symbols(w))
+}
+
+// returns a deep copy of d
+func (d *DFA) Copy() *DFA {
+	c := &DFA{symbols: d.symbols, start: d.start, accept: append([]bool{}, d.accept...), trans: make([][]int, len(d.trans))}
+	for s := range d.trans {
+		c.trans[s] = append([]int{}, d.trans[s]...)
+	}
+	return c
+}
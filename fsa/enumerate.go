@@ -0,0 +1,181 @@
+package fsa
+
+import (
+	"iter"
+	"math/big"
+	"slices"
+)
+
+// Words iterates over the words accepted by d of length at most maxLen, in shortlex order of symbols
+// Only paths that lead to an accepting state within the remaining length are explored, so the time is proportional to the output (times the number of symbols and maxLen)
+// The yielded slice is reused between iterations, copy it to keep it
+func (d *DFA) Words(maxLen int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		// good[r][s] is true when an accepting state can be reached from s in exactly r steps
+		good := make([][]bool, maxLen+1)
+		good[0] = append([]bool{}, d.accept...)
+		for r := 1; r <= maxLen; r++ {
+			good[r] = make([]bool, len(d.accept))
+			for s := range d.trans {
+				for _, t := range d.trans[s] {
+					if t >= 0 && good[r-1][t] {
+						good[r][s] = true
+						break
+					}
+				}
+			}
+		}
+		word := make([]int, 0, maxLen)
+		var visit func(s, r int) bool
+		visit = func(s, r int) bool { //yields the accepted words of length len(word) + r
+			if r == 0 {
+				return yield(word)
+			}
+			for a, t := range d.trans[s] {
+				if t >= 0 && good[r-1][t] {
+					word = append(word, a)
+					ok := visit(t, r-1)
+					word = word[:len(word)-1]
+					if !ok {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		for length := 0; length <= maxLen; length++ {
+			if good[length][d.start] && !visit(d.start, length) {
+				return
+			}
+		}
+	}
+}
+
+// CountByLength returns the number of words of each length 0, 1, ..., maxLen accepted by d
+// The counts grow exponentially in general, hence the big integers
+func (d *DFA) CountByLength(maxLen int) []*big.Int {
+	counts := make([]*big.Int, maxLen+1)
+	// paths[s] is the number of words of the current length leading from the start to s
+	paths := make([]*big.Int, len(d.accept))
+	for s := range paths {
+		paths[s] = new(big.Int)
+	}
+	paths[d.start].SetInt64(1)
+	for length := 0; length <= maxLen; length++ {
+		counts[length] = new(big.Int)
+		for s, ok := range d.accept {
+			if ok {
+				counts[length].Add(counts[length], paths[s])
+			}
+		}
+		next := make([]*big.Int, len(d.accept))
+		for s := range next {
+			next[s] = new(big.Int)
+		}
+		for s := range d.trans {
+			if paths[s].Sign() == 0 {
+				continue
+			}
+			for _, t := range d.trans[s] {
+				if t >= 0 {
+					next[t].Add(next[t], paths[s])
+				}
+			}
+		}
+		paths = next
+	}
+	return counts
+}
+
+// ShortestWord returns a shortest word accepted by d (the first in shortlex order of symbols), or false if d accepts nothing
+func (d *DFA) ShortestWord() ([]int, bool) {
+	// breadth first search, remembering how we first reached each state
+	prev := make([][2]int, len(d.accept)) //previous state and symbol
+	seen := make([]bool, len(d.accept))
+	seen[d.start] = true
+	queue := []int{d.start}
+	for i := 0; i < len(queue); i++ {
+		s := queue[i]
+		if d.accept[s] {
+			word := make([]int, 0)
+			for s != d.start {
+				word = append(word, prev[s][1])
+				s = prev[s][0]
+			}
+			slices.Reverse(word)
+			return word, true
+		}
+		for a, t := range d.trans[s] {
+			if t >= 0 && !seen[t] {
+				seen[t] = true
+				prev[t] = [2]int{s, a}
+				queue = append(queue, t)
+			}
+		}
+	}
+	return nil, false
+}
+
+// GrowthSeries returns the generating function sum_n c_n t^n of the numbers c_n of accepted words of length n,
+// as a rational function num(t) / den(t) given by coefficient slices (constant term first) with den(0) = 1 and no common factor
+// We find the shortest linear recurrence of the counts with the Berlekamp-Massey algorithm, which only needs 2 * NumStates() of them
+// since the denominator divides det(1 - tA) for the transition matrix A
+func (d *DFA) GrowthSeries() (num, den []*big.Int) {
+	d = d.Trim()
+	counts := d.CountByLength(2*d.NumStates() + 1)
+	s := make([]*big.Rat, len(counts))
+	for i, c := range counts {
+		s[i] = new(big.Rat).SetInt(c)
+	}
+	// connection polynomial c of the current shortest recurrence of length l, and b the one before the last length change
+	c, b := []*big.Rat{big.NewRat(1, 1)}, []*big.Rat{big.NewRat(1, 1)}
+	l, m, lastDiscrepancy := 0, 1, big.NewRat(1, 1)
+	for n := range s {
+		discrepancy := new(big.Rat).Set(s[n])
+		for i := 1; i <= l && i < len(c); i++ {
+			discrepancy.Add(discrepancy, new(big.Rat).Mul(c[i], s[n-i]))
+		}
+		if discrepancy.Sign() == 0 {
+			m++
+			continue
+		}
+		// c -= discrepancy / lastDiscrepancy * t^m * b
+		factor := new(big.Rat).Quo(discrepancy, lastDiscrepancy)
+		old := slices.Clone(c)
+		for len(c) < len(b)+m {
+			c = append(c, new(big.Rat))
+		}
+		for i, x := range b {
+			c[i+m] = new(big.Rat).Sub(c[i+m], new(big.Rat).Mul(factor, x))
+		}
+		if 2*l <= n {
+			l, b, lastDiscrepancy, m = n+1-l, old, discrepancy, 1
+		} else {
+			m++
+		}
+	}
+	// the numerator is the series times the denominator, which vanishes from degree l on
+	den = make([]*big.Int, l+1)
+	for i := range den {
+		den[i] = new(big.Int)
+		if i < len(c) {
+			den[i].Set(c[i].Num()) //c has integer coefficients, see Fatou's lemma
+		}
+	}
+	num = make([]*big.Int, l)
+	for n := range num {
+		num[n] = new(big.Int)
+		for i := 0; i <= n; i++ {
+			num[n].Add(num[n], new(big.Int).Mul(den[i], counts[n-i]))
+		}
+	}
+	return trimZeros(num), trimZeros(den)
+}
+
+// removes the zero coefficients of highest degree
+func trimZeros(p []*big.Int) []*big.Int {
+	for len(p) > 0 && p[len(p)-1].Sign() == 0 {
+		p = p[:len(p)-1]
+	}
+	return p
+}
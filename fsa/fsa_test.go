@@ -0,0 +1,253 @@
+package fsa_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/fsa"
+)
+
+// the freely reduced words on the given number of generators, remembering the last letter read
+func freelyReduced(generators int) *fsa.DFA {
+	n := fsa.GeneratorSymbols(generators)
+	d := fsa.NewDFA(n)
+	d.SetAccept(0, true)
+	for range n {
+		d.AddState(true) //state a+1 means the last letter was a
+	}
+	for s := range n + 1 {
+		for a := range n {
+			if s == 0 || fsa.InverseSymbol(s-1) != a {
+				d.SetTransition(s, a, a+1)
+			}
+		}
+	}
+	return d
+}
+
+// the words (over the letters of the given number of generators) containing sub
+func containing(generators int, sub [][2]int) *fsa.DFA {
+	n := fsa.GeneratorSymbols(generators)
+	nfa := fsa.NewNFA(n)
+	symbols := fsa.Symbols(sub)
+	for i := range len(symbols) + 1 {
+		nfa.AddState(i == len(symbols))
+	}
+	nfa.AddStart(0)
+	for a := range n {
+		nfa.AddTransition(0, a, 0)
+		nfa.AddTransition(len(symbols), a, len(symbols))
+	}
+	for i, a := range symbols {
+		nfa.AddTransition(i, a, i+1)
+	}
+	return nfa.Determinize()
+}
+
+func TestAlphabet(t *testing.T) {
+	w := [][2]int{{0, 2}, {1, -1}, {3, 1}}
+	if got := fsa.Symbols(w); !slices.Equal(got, []int{0, 0, 3, 6}) {
+		t.Fatalf("Symbols(%v) = %v", w, got)
+	}
+	if got := fsa.Letters(fsa.Symbols(w)); !slices.Equal(got, w) {
+		t.Fatalf("Letters(Symbols(%v)) = %v", w, got)
+	}
+}
+
+func TestCountByLength(t *testing.T) {
+	got := freelyReduced(2).CountByLength(4)
+	want := []int64{1, 4, 12, 36, 108}
+	for i := range want {
+		if got[i].Int64() != want[i] {
+			t.Fatalf("CountByLength(4) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	words := make([][][2]int, 0)
+	for w := range freelyReduced(1).Words(2) {
+		words = append(words, fsa.Letters(w))
+	}
+	want := [][][2]int{{}, {{0, 1}}, {{0, -1}}, {{0, 2}}, {{0, -2}}}
+	if !slices.EqualFunc(words, want, func(u, v [][2]int) bool { return slices.Equal(u, v) }) {
+		t.Fatalf("Words(2) = %v, want %v", words, want)
+	}
+	count := 0
+	for range containing(2, [][2]int{{0, 1}, {1, 1}}).Words(3) {
+		count++
+	}
+	if count != 9 { //ab, then ab followed or preceded by one of 4 letters
+		t.Fatalf("expected 9 words of length at most 3 containing ab, got %v", count)
+	}
+}
+
+func TestDeterminize(t *testing.T) {
+	d := containing(2, [][2]int{{0, -1}, {1, 1}})
+	tests := []struct {
+		w    [][2]int
+		want bool
+	}{
+		{[][2]int{{0, -1}, {1, 1}}, true},
+		{[][2]int{{1, 3}, {0, -2}, {1, 1}, {0, 1}}, true},
+		{[][2]int{{1, 1}, {0, -1}}, false},
+		{[][2]int{}, false},
+	}
+	for _, tt := range tests {
+		if got := d.AcceptsWord(tt.w); got != tt.want {
+			t.Fatalf("AcceptsWord(%v) = %v, want %v", tt.w, got, tt.want)
+		}
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	// containing a: the subset construction gives the subsets {0} and {0,1}, which is already minimal
+	d := containing(1, [][2]int{{0, 1}})
+	if m := d.Minimize(); m.NumStates() != 2 || !fsa.Equivalent(d, m) {
+		t.Fatalf("expected 2 states, got %v", m.NumStates())
+	}
+	// all freely reduced words of F_1: the states after a and a^-1 differ, so we keep 3 states
+	if m := freelyReduced(1).Minimize(); m.NumStates() != 3 {
+		t.Fatalf("expected 3 states, got %v", m.NumStates())
+	}
+	// a DFA with two copies of the same state and an unreachable and a dead state
+	e := fsa.NewDFA(2)
+	s1, s2, unreachable, dead := e.AddState(true), e.AddState(true), e.AddState(true), e.AddState(false)
+	e.SetTransition(0, 0, s1)
+	e.SetTransition(0, 1, s2)
+	e.SetTransition(s1, 0, s2)
+	e.SetTransition(s2, 0, s1)
+	e.SetTransition(s1, 1, dead)
+	e.SetTransition(unreachable, 0, 0)
+	if m := e.Minimize(); m.NumStates() != 2 || !fsa.Equivalent(e, m) {
+		t.Fatalf("expected 2 states, got %v", m.NumStates())
+	}
+	// minimal DFAs of equivalent automata are identical
+	u := fsa.Union(containing(1, [][2]int{{0, 2}}), containing(1, [][2]int{{0, 3}}))
+	if m1, m2 := u.Minimize(), containing(1, [][2]int{{0, 2}}).Minimize(); m1.NumStates() != m2.NumStates() || !fsa.Equivalent(m1, m2) {
+		t.Fatalf("minimizations differ")
+	}
+	// all words, where every state accepts, so the first refinement splits nothing
+	all := fsa.NewDFA(2)
+	all.SetAccept(0, true)
+	other := all.AddState(true)
+	all.SetTransition(0, 0, other)
+	all.SetTransition(0, 1, 0)
+	all.SetTransition(other, 0, 0)
+	all.SetTransition(other, 1, other)
+	if m := all.Minimize(); m.NumStates() != 1 || !m.Accepts([]int{}) || !m.Accepts([]int{0, 1, 1, 0}) {
+		t.Fatalf("expected a single accepting state, got %v states", m.NumStates())
+	}
+	// the empty language, with an accepting state that can't be reached
+	none := fsa.NewDFA(2)
+	none.SetTransition(0, 0, 0)
+	none.SetTransition(none.AddState(true), 1, 0)
+	if m := none.Minimize(); m.NumStates() != 1 || m.Accepts([]int{}) || m.Accepts([]int{0}) {
+		t.Fatalf("expected a single rejecting state, got %v states", m.NumStates())
+	}
+	if m := none.Trim(); m.NumStates() != 1 || m.Next(0, 0) != -1 || m.Next(0, 1) != -1 {
+		t.Fatalf("expected a single state without transitions, got %v states", m.NumStates())
+	}
+}
+
+func TestBooleanOperations(t *testing.T) {
+	a, b := containing(2, [][2]int{{0, 1}}), containing(2, [][2]int{{1, 1}})
+	both := fsa.Intersect(a, b)
+	either := fsa.Union(a, b)
+	onlyA := fsa.Difference(a, b)
+	tests := []struct {
+		w                   [][2]int
+		both, either, onlyA bool
+	}{
+		{[][2]int{{0, 1}, {1, 1}}, true, true, false},
+		{[][2]int{{0, 2}}, false, true, true},
+		{[][2]int{{1, -1}}, false, false, false},
+	}
+	for _, tt := range tests {
+		if both.AcceptsWord(tt.w) != tt.both || either.AcceptsWord(tt.w) != tt.either || onlyA.AcceptsWord(tt.w) != tt.onlyA {
+			t.Fatalf("wrong boolean operations on %v", tt.w)
+		}
+	}
+	if !fsa.Equivalent(fsa.Intersect(a, a.Complement()), fsa.NewDFA(4)) {
+		t.Fatalf("a language and its complement should not intersect")
+	}
+	if !fsa.Intersect(a, a.Complement()).IsEmpty() || a.IsEmpty() {
+		t.Fatalf("wrong emptiness checks")
+	}
+}
+
+func TestIsFinite(t *testing.T) {
+	// NewDFA accepts nothing, so this is all freely reduced words
+	reduced := fsa.Intersect(freelyReduced(1), fsa.NewDFA(2).Complement())
+	if reduced.IsFinite() || !fsa.Equivalent(reduced, freelyReduced(1)) {
+		t.Fatalf("all freely reduced words should be infinite")
+	}
+	d := fsa.NewDFA(2)
+	s := d.AddState(true)
+	d.SetTransition(0, 0, s)
+	dead := d.AddState(false)
+	d.SetTransition(s, 1, dead)
+	d.SetTransition(dead, 1, dead) //a cycle, but not through useful states
+	if !d.IsFinite() {
+		t.Fatalf("{a} should be finite")
+	}
+	if d.NFA().Determinize().NumStates() != 3 || !fsa.Equivalent(d.NFA().Determinize(), d) {
+		t.Fatalf("round trip through NFA should not change the DFA")
+	}
+}
+
+// the pairs (u, ua) of freely reduced words where a is the first generator, as a two-tape automaton
+func rightMultiplier() *fsa.DFA {
+	reduced := freelyReduced(2)
+	k := 4
+	pad := fsa.Padding(k)
+	n := fsa.NewNFA(fsa.PairSymbols(k))
+	n.AddState(false) //reading (x, x)
+	n.AddState(true)  //read (pad, a)
+	n.AddStart(0)
+	for x := range k {
+		n.AddTransition(0, fsa.PairSymbol(x, x, k), 0)
+	}
+	n.AddTransition(0, fsa.PairSymbol(pad, 0, k), 1)
+	return fsa.Intersect(n.Determinize(), fsa.Intersect(fsa.Lift(reduced, 0, k), fsa.Lift(reduced, 1, k)))
+}
+
+func TestPairs(t *testing.T) {
+	k := 4
+	a, A, b := 0, 1, 2
+	M := rightMultiplier()
+	tests := []struct {
+		name string
+		u, v []int
+		want bool
+	}{
+		{"append", []int{b}, []int{b, a}, true},
+		{"from the empty word", []int{}, []int{a}, true},
+		{"not freely reduced", []int{A}, []int{A, a}, false},
+		{"different prefix", []int{b}, []int{a, a}, false},
+	}
+	for _, tt := range tests {
+		if got := M.AcceptsPair(tt.u, tt.v, k); got != tt.want {
+			t.Errorf("%v: AcceptsPair(%v, %v) = %v, want %v", tt.name, tt.u, tt.v, got, tt.want)
+		}
+	}
+	if P := fsa.Projection(M, 1, k); !P.Accepts([]int{b, a}) || P.Accepts([]int{a, b}) || P.Accepts([]int{}) {
+		t.Errorf("second projection is not the reduced words ending in a")
+	}
+	if !fsa.Swap(M, k).AcceptsPair([]int{b, a}, []int{b}, k) {
+		t.Errorf("Swap does not exchange the tapes")
+	}
+	// multiplying by a twice
+	twice := fsa.Compose(M, M, k)
+	if !twice.AcceptsPair([]int{b}, []int{b, a, a}, k) || twice.AcceptsPair([]int{b}, []int{b, a}, k) {
+		t.Errorf("wrong composite")
+	}
+	// the middle word can be shorter than both
+	back := fsa.Compose(fsa.Swap(M, k), M, k)
+	if !back.AcceptsPair([]int{b, a}, []int{b, a}, k) || back.AcceptsPair([]int{b, a}, []int{b}, k) {
+		t.Errorf("wrong composite through a shorter word")
+	}
+	if !fsa.Difference(fsa.Compose(M, fsa.Swap(M, k), k), fsa.Diagonal(k)).IsEmpty() {
+		t.Errorf("multiplying by a is not injective")
+	}
+}
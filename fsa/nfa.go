@@ -0,0 +1,128 @@
+package fsa
+
+import "slices"
+
+// A nondeterministic finite state automaton with ε-transitions
+// States are 0, 1, ..., NumStates()-1
+type NFA struct {
+	symbols int
+	start   []int
+	accept  []bool
+	trans   [][][]int //trans[s][a] are the states reached from s by reading a
+	eps     [][]int   //eps[s] are the states reached from s by an ε-transition
+}
+
+// Constructor for an NFA over symbols symbols, without states
+func NewNFA(symbols int) *NFA {
+	return &NFA{symbols: symbols}
+}
+
+// adds a state without transitions and returns it
+func (n *NFA) AddState(accept bool) int {
+	n.trans = append(n.trans, make([][]int, n.symbols))
+	n.eps = append(n.eps, nil)
+	n.accept = append(n.accept, accept)
+	return len(n.accept) - 1
+}
+
+func (n *NFA) AddStart(s int)               { n.start = append(n.start, s) }
+func (n *NFA) SetAccept(s int, accept bool) { n.accept[s] = accept }
+func (n *NFA) AddTransition(s, a, t int)    { n.trans[s][a] = append(n.trans[s][a], t) }
+func (n *NFA) AddEpsilon(s, t int)          { n.eps[s] = append(n.eps[s], t) }
+func (n *NFA) NumStates() int               { return len(n.accept) }
+func (n *NFA) NumSymbols() int              { return n.symbols }
+
+// the ε-closure of a set of states, as a sorted slice without duplicates
+func (n *NFA) closure(states []int) []int {
+	seen := make(map[int]bool, len(states))
+	stack := append([]int{}, states...)
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		stack = append(stack, n.eps[s]...)
+	}
+	closed := make([]int, 0, len(seen))
+	for s := range seen {
+		closed = append(closed, s)
+	}
+	slices.Sort(closed)
+	return closed
+}
+
+// the states reached from a set of states by reading a, ε-closed
+func (n *NFA) step(states []int, a int) []int {
+	next := make([]int, 0)
+	for _, s := range states {
+		next = append(next, n.trans[s][a]...)
+	}
+	return n.closure(next)
+}
+
+// checks whether n accepts the word of symbols
+func (n *NFA) Accepts(word []int) bool {
+	states := n.closure(n.start)
+	for _, a := range word {
+		states = n.step(states, a)
+	}
+	return slices.ContainsFunc(states, func(s int) bool { return n.accept[s] })
+}
+
+// checks whether n accepts the word given as (generator, exponent) syllables, see Symbols
+func (n *NFA) AcceptsWord(w [][2]int) bool {
+	return n.Accepts(Symbols(w))
+}
+
+func subsetKey(states []int) string {
+	b := make([]byte, 0, 8*len(states))
+	for _, s := range states {
+		b = appendInt(b, s)
+	}
+	return string(b)
+}
+
+// Determinize returns a DFA accepting the same words, by the subset construction
+// Only the subsets reachable from the start are built, and the empty subset is left out so the DFA is partial
+// In the worst case this has 2^NumStates() states
+func (n *NFA) Determinize() *DFA {
+	d := &DFA{symbols: n.symbols}
+	ids := make(map[string]int)
+	subsets := make([][]int, 0)
+	add := func(states []int) int {
+		key := subsetKey(states)
+		if id, ok := ids[key]; ok {
+			return id
+		}
+		id := d.AddState(slices.ContainsFunc(states, func(s int) bool { return n.accept[s] }))
+		ids[key] = id
+		subsets = append(subsets, states)
+		return id
+	}
+	add(n.closure(n.start))
+	for i := 0; i < len(subsets); i++ { //subsets grows as we go
+		for a := range n.symbols {
+			if next := n.step(subsets[i], a); len(next) > 0 {
+				d.trans[i][a] = add(next)
+			}
+		}
+	}
+	return d
+}
+
+// returns an NFA accepting the same words as d
+func (d *DFA) NFA() *NFA {
+	n := NewNFA(d.symbols)
+	for s := range d.accept {
+		n.AddState(d.accept[s])
+		for a, t := range d.trans[s] {
+			if t >= 0 {
+				n.trans[s][a] = []int{t}
+			}
+		}
+	}
+	n.AddStart(d.start)
+	return n
+}
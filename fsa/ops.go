@@ -0,0 +1,283 @@
+package fsa
+
+// returns a complete DFA accepting the same words as d, adding a non-accepting sink state if some transition is missing
+func (d *DFA) Complete() *DFA {
+	c := d.Copy()
+	sink := -1
+	for s := range c.trans {
+		for a, t := range c.trans[s] {
+			if t < 0 {
+				if sink < 0 {
+					sink = c.AddState(false)
+					for b := range c.symbols {
+						c.trans[sink][b] = sink
+					}
+				}
+				c.trans[s][a] = sink
+			}
+		}
+	}
+	return c
+}
+
+// states reachable from the start
+func (d *DFA) reachable() []bool {
+	seen := make([]bool, len(d.accept))
+	seen[d.start] = true
+	stack := []int{d.start}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, t := range d.trans[s] {
+			if t >= 0 && !seen[t] {
+				seen[t] = true
+				stack = append(stack, t)
+			}
+		}
+	}
+	return seen
+}
+
+// states from which an accepting state can be reached
+func (d *DFA) coreachable() []bool {
+	reverse := make([][]int, len(d.accept))
+	for s := range d.trans {
+		for _, t := range d.trans[s] {
+			if t >= 0 {
+				reverse[t] = append(reverse[t], s)
+			}
+		}
+	}
+	seen := make([]bool, len(d.accept))
+	stack := make([]int, 0)
+	for s, ok := range d.accept {
+		if ok {
+			seen[s] = true
+			stack = append(stack, s)
+		}
+	}
+	for len(stack) > 0 {
+		t := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, s := range reverse[t] {
+			if !seen[s] {
+				seen[s] = true
+				stack = append(stack, s)
+			}
+		}
+	}
+	return seen
+}
+
+// keeps the states in keep (which must contain the start), renumbered in breadth first order from the start
+// transitions to other states are removed
+func (d *DFA) restrict(keep []bool) *DFA {
+	ids := make([]int, len(d.accept))
+	for s := range ids {
+		ids[s] = -1
+	}
+	r := &DFA{symbols: d.symbols}
+	order := []int{d.start}
+	ids[d.start] = r.AddState(d.accept[d.start])
+	for i := 0; i < len(order); i++ {
+		for _, t := range d.trans[order[i]] {
+			if t >= 0 && keep[t] && ids[t] < 0 {
+				ids[t] = r.AddState(d.accept[t])
+				order = append(order, t)
+			}
+		}
+	}
+	for i, s := range order {
+		for a, t := range d.trans[s] {
+			if t >= 0 {
+				r.trans[i][a] = ids[t]
+			}
+		}
+	}
+	return r
+}
+
+// Trim returns a partial DFA accepting the same words with only the useful states, i.e. reachable from the start and coreachable to an accepting state
+// The start is always kept, so the trim of a DFA accepting nothing has a single state and no transitions
+func (d *DFA) Trim() *DFA {
+	keep := d.coreachable()
+	if !keep[d.start] {
+		return NewDFA(d.symbols) //all states reachable from the start are dead too, and restrict would keep loops on the start
+	}
+	return d.restrict(keep)
+}
+
+// Minimize returns the minimal (trim) DFA accepting the same words, by Moore's partition refinement
+// States are numbered in breadth first order from the start (trying symbols in increasing order), so two DFAs accept the same words exactly when their minimizations are identical
+func (d *DFA) Minimize() *DFA {
+	t := d.Trim()
+	n := len(t.accept)
+	// class -1 stands for the missing transitions, i.e. the dead sink
+	class := make([]int, n)
+	for s := range class {
+		if t.accept[s] {
+			class[s] = 1
+		}
+	}
+	for {
+		// the signature of a state is its class and the classes of its successors
+		ids := make(map[string]int)
+		next := make([]int, n)
+		for s := range next {
+			sig := make([]byte, 0, 8*(t.symbols+1))
+			sig = appendInt(sig, class[s])
+			for _, u := range t.trans[s] {
+				c := -1
+				if u >= 0 {
+					c = class[u]
+				}
+				sig = appendInt(sig, c)
+			}
+			id, ok := ids[string(sig)]
+			if !ok {
+				id = len(ids)
+				ids[string(sig)] = id
+			}
+			next[s] = id
+		}
+		split := len(ids) != countClasses(class)
+		class = next //numbered densely from 0, unlike the first classes
+		if !split {
+			break
+		}
+	}
+	// one state per class
+	m := &DFA{symbols: t.symbols, start: class[t.start]}
+	classes := countClasses(class)
+	m.trans = make([][]int, classes)
+	m.accept = make([]bool, classes)
+	for s := range class {
+		c := class[s]
+		m.accept[c] = t.accept[s]
+		m.trans[c] = make([]int, t.symbols)
+		for a, u := range t.trans[s] {
+			m.trans[c][a] = -1
+			if u >= 0 {
+				m.trans[c][a] = class[u]
+			}
+		}
+	}
+	all := make([]bool, classes)
+	for c := range all {
+		all[c] = true
+	}
+	return m.restrict(all) //renumber canonically
+}
+
+func countClasses(class []int) int {
+	seen := make(map[int]bool)
+	for _, c := range class {
+		seen[c] = true
+	}
+	return len(seen)
+}
+
+func appendInt(b []byte, x int) []byte {
+	for range 8 {
+		b = append(b, byte(x))
+		x >>= 8
+	}
+	return b
+}
+
+// Product runs a and b in parallel, accepting when accept(a accepts, b accepts) is true
+// Both must have the same alphabet, and only the pairs of states reachable from the start are built
+func Product(a, b *DFA, accept func(bool, bool) bool) *DFA {
+	if a.symbols != b.symbols {
+		panic("fsa: product of automata over different alphabets")
+	}
+	ca, cb := a.Complete(), b.Complete() //so that e.g. the union keeps going when one of them rejects
+	p := &DFA{symbols: a.symbols}
+	ids := make(map[[2]int]int)
+	pairs := make([][2]int, 0)
+	add := func(s, t int) int {
+		if id, ok := ids[[2]int{s, t}]; ok {
+			return id
+		}
+		id := p.AddState(accept(ca.accept[s], cb.accept[t]))
+		ids[[2]int{s, t}] = id
+		pairs = append(pairs, [2]int{s, t})
+		return id
+	}
+	add(ca.start, cb.start)
+	for i := 0; i < len(pairs); i++ {
+		for x := range p.symbols {
+			p.trans[i][x] = add(ca.trans[pairs[i][0]][x], cb.trans[pairs[i][1]][x])
+		}
+	}
+	return p
+}
+
+// accepts the words accepted by both a and b
+func Intersect(a, b *DFA) *DFA {
+	return Product(a, b, func(x, y bool) bool { return x && y }).Trim()
+}
+
+// accepts the words accepted by a or b
+func Union(a, b *DFA) *DFA {
+	return Product(a, b, func(x, y bool) bool { return x || y }).Trim()
+}
+
+// accepts the words accepted by a but not b
+func Difference(a, b *DFA) *DFA {
+	return Product(a, b, func(x, y bool) bool { return x && !y }).Trim()
+}
+
+// accepts exactly the words (over the same alphabet) that d rejects
+func (d *DFA) Complement() *DFA {
+	c := d.Complete()
+	for s := range c.accept {
+		c.accept[s] = !c.accept[s]
+	}
+	return c
+}
+
+// checks whether d accepts no word at all
+func (d *DFA) IsEmpty() bool {
+	reach := d.reachable()
+	for s, ok := range d.accept {
+		if ok && reach[s] {
+			return false
+		}
+	}
+	return true
+}
+
+// checks whether d accepts finitely many words, i.e. whether there is no cycle through useful states
+func (d *DFA) IsFinite() bool {
+	t := d.Trim()
+	if t.IsEmpty() {
+		return true
+	}
+	// depth first search for a back edge
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make([]int, len(t.accept))
+	var cyclic func(int) bool
+	cyclic = func(s int) bool {
+		state[s] = active
+		for _, u := range t.trans[s] {
+			if u < 0 {
+				continue
+			} else if state[u] == active || (state[u] == unvisited && cyclic(u)) {
+				return true
+			}
+		}
+		state[s] = done
+		return false
+	}
+	return !cyclic(t.start)
+}
+
+// checks whether a and b accept the same words
+func Equivalent(a, b *DFA) bool {
+	return Product(a, b, func(x, y bool) bool { return x != y }).IsEmpty()
+}
@@ -0,0 +1,180 @@
+package fsa
+
+// Two-tape automata read pairs of words (u, v) synchronously, one letter of each at a time
+// The shorter word is padded at the end with the padding symbol $, so over an alphabet of k symbols the pair alphabet has the symbols (a, b) with a, b in 0, ..., k where k is $
+// The pair ($, $) never occurs, and once a tape reads $ it only reads $ afterwards
+// Multiplier automata of automatic groups are such automata
+
+// The number of symbols of the pair alphabet over an alphabet of symbols symbols, including the unused ($, $)
+func PairSymbols(symbols int) int { return (symbols + 1) * (symbols + 1) }
+
+// The padding symbol $ of an alphabet of symbols symbols
+func Padding(symbols int) int { return symbols }
+
+// The pair symbol (a, b), where a or b may be Padding(symbols)
+func PairSymbol(a, b, symbols int) int { return a*(symbols+1) + b }
+
+// The components of a pair symbol, the inverse of PairSymbol
+func SplitPairSymbol(p, symbols int) (int, int) { return p / (symbols + 1), p % (symbols + 1) }
+
+// Pads the shorter of u and v and reads them synchronously as a word of pair symbols
+func PadPair(u, v []int, symbols int) []int {
+	pad := Padding(symbols)
+	pair := make([]int, max(len(u), len(v)))
+	for i := range pair {
+		a, b := pad, pad
+		if i < len(u) {
+			a = u[i]
+		}
+		if i < len(v) {
+			b = v[i]
+		}
+		pair[i] = PairSymbol(a, b, symbols)
+	}
+	return pair
+}
+
+// checks whether d, an automaton over the pair alphabet, accepts (u, v)
+func (d *DFA) AcceptsPair(u, v []int, symbols int) bool {
+	return d.Accepts(PadPair(u, v, symbols))
+}
+
+// Lift turns d, over an alphabet of symbols symbols, into an automaton over the pair alphabet accepting the padded pairs whose tape (0 or 1) is accepted by d
+// It also makes sure that the padding on that tape only occurs at the end
+func Lift(d *DFA, tape, symbols int) *DFA {
+	pad := Padding(symbols)
+	n := d.NumStates()
+	l := &DFA{symbols: PairSymbols(symbols), start: d.start}
+	for s := range 2 * n { //s < n reads letters, n+s has read s and then padding
+		l.AddState(d.accept[s%n])
+	}
+	for p := range l.symbols {
+		a, b := SplitPairSymbol(p, symbols)
+		if a == pad && b == pad {
+			continue
+		}
+		if tape == 1 {
+			a = b
+		}
+		for s := range n {
+			if a == pad {
+				l.trans[s][p] = n + s
+				l.trans[n+s][p] = n + s
+			} else if t := d.trans[s][a]; t >= 0 {
+				l.trans[s][p] = t
+			}
+		}
+	}
+	return l
+}
+
+// Projection returns an automaton accepting the words on tape (0 or 1) of the pairs accepted by d, with the padding removed
+func Projection(d *DFA, tape, symbols int) *DFA {
+	pad := Padding(symbols)
+	n := NewNFA(symbols)
+	for s := range d.accept {
+		n.AddState(d.accept[s])
+	}
+	n.AddStart(d.start)
+	for s := range d.trans {
+		for p, t := range d.trans[s] {
+			if t < 0 {
+				continue
+			}
+			a, b := SplitPairSymbol(p, symbols)
+			if tape == 1 {
+				a = b
+			}
+			if a == pad {
+				n.AddEpsilon(s, t)
+			} else {
+				n.AddTransition(s, a, t)
+			}
+		}
+	}
+	return n.Determinize()
+}
+
+// Swap exchanges the two tapes of d, so it accepts (v, u) exactly when d accepts (u, v)
+func Swap(d *DFA, symbols int) *DFA {
+	s := d.Copy()
+	for state := range d.trans {
+		for p, t := range d.trans[state] {
+			a, b := SplitPairSymbol(p, symbols)
+			s.trans[state][PairSymbol(b, a, symbols)] = t
+		}
+	}
+	return s
+}
+
+// Compose accepts (u, w) exactly when there is some v with (u, v) accepted by a and (v, w) accepted by b
+// v may be longer than both u and w, which we read as ε-moves of the composite, or shorter than both,
+// in which case a (or b) has read its whole input and stays in its last state
+func Compose(a, b *DFA, symbols int) *DFA {
+	pad := Padding(symbols)
+	n := NewNFA(PairSymbols(symbols))
+	// a state of the composite is a state of a, a state of b and whether each of them has read its whole input
+	type state struct {
+		a, b         int
+		aDone, bDone bool
+	}
+	ids := make(map[state]int)
+	states := make([]state, 0)
+	add := func(s state) int {
+		if id, ok := ids[s]; ok {
+			return id
+		}
+		id := n.AddState(a.accept[s.a] && b.accept[s.b])
+		ids[s] = id
+		states = append(states, s)
+		return id
+	}
+	// the next state of d after reading (x, y), given whether d is done
+	next := func(d *DFA, s int, done bool, x, y int) (int, bool, bool) {
+		if x == pad && y == pad {
+			return s, true, true
+		} else if done {
+			return -1, false, false
+		}
+		t := d.trans[s][PairSymbol(x, y, symbols)]
+		return t, false, t >= 0
+	}
+	n.AddStart(add(state{a: a.start, b: b.start}))
+	for i := 0; i < len(states); i++ {
+		s := states[i]
+		for x := range symbols + 1 {
+			for y := range symbols + 1 {
+				sa, aDone, ok := next(a, s.a, s.aDone, x, y)
+				if !ok {
+					continue
+				}
+				for z := range symbols + 1 {
+					if x == pad && y == pad && z == pad {
+						continue
+					}
+					sb, bDone, ok := next(b, s.b, s.bDone, y, z)
+					if !ok {
+						continue
+					}
+					t := add(state{a: sa, b: sb, aDone: aDone, bDone: bDone})
+					if x == pad && z == pad {
+						n.AddEpsilon(i, t)
+					} else {
+						n.AddTransition(i, PairSymbol(x, z, symbols), t)
+					}
+				}
+			}
+		}
+	}
+	return n.Determinize()
+}
+
+// Diagonal accepts the pairs (u, u) for all words u over an alphabet of symbols symbols
+func Diagonal(symbols int) *DFA {
+	d := &DFA{symbols: PairSymbols(symbols)}
+	d.AddState(true)
+	for a := range symbols {
+		d.trans[0][PairSymbol(a, a, symbols)] = 0
+	}
+	return d
+}
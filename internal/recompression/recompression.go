@@ -0,0 +1,328 @@
+// Package recompression decides whether two grammar-compressed words are equal without expanding them, with Jeż's recompression
+// Both words are compressed side by side: maximal blocks a^l become fresh letters, then pairs ab become fresh letters
+// for each a in L and b in R, for a partition L, R of the letters. The same block or pair always becomes the same letter,
+// so the compressed words are equal exactly when the original ones are. Each round shortens the words by a constant factor,
+// so after O(log n) rounds both are single letters and we compare those
+// Blocks and pairs crossing the boundary of a rule are first popped out of it, so everything happens on the grammar
+// and the whole test is deterministic and polynomial in the size of the grammar and log n
+package recompression
+
+import (
+	"slices"
+)
+
+// A Grammar is a straight-line program: every symbol derives a single nonempty word over letters 0, 1, 2, ...
+// Symbols are only ever added, so they stay valid. The zero value is an empty Grammar ready to use
+// Lengths must fit in an int
+type Grammar struct {
+	rules [][]item
+	lens  []int
+}
+
+// one term of a rule: count > 0 copies of the letter sym, or the symbol sym if count is 0
+type item struct {
+	sym   int
+	count int
+}
+
+func (g *Grammar) add(body []item, n int) int {
+	g.rules = append(g.rules, body)
+	g.lens = append(g.lens, n)
+	return len(g.rules) - 1
+}
+
+// Run returns a symbol deriving count > 0 copies of the letter a >= 0
+func (g *Grammar) Run(a, count int) int {
+	return g.add([]item{{a, count}}, count)
+}
+
+// Concat returns a symbol deriving the word of x followed by the word of y
+func (g *Grammar) Concat(x, y int) int {
+	return g.add([]item{{x, 0}, {y, 0}}, g.lens[x]+g.lens[y])
+}
+
+// Pow returns a symbol deriving n > 0 copies of the word of x, by repeated squaring
+func (g *Grammar) Pow(x, n int) int {
+	p := -1
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			if p < 0 {
+				p = x
+			} else {
+				p = g.Concat(p, x)
+			}
+		}
+		if n > 1 {
+			x = g.Concat(x, x)
+		}
+	}
+	return p
+}
+
+// Len returns the length of the word derived by x
+func (g *Grammar) Len(x int) int {
+	return g.lens[x]
+}
+
+// Equal reports whether x and y derive the same word
+func (g *Grammar) Equal(x, y int) bool {
+	if g.lens[x] != g.lens[y] {
+		return false
+	}
+	return x == y || newState(g, x, y).equal()
+}
+
+// the rules used by the two words being compared, as they get compressed
+type state struct {
+	rules [][]item       //children come before their parents, and the last two rules are the two words
+	next  int            //the next fresh letter
+	fresh map[[3]int]int //the letters replacing blocks {0, a, l} and pairs {1, a, b}
+}
+
+// copies the rules needed for x and y, so that g itself is never changed
+func newState(g *Grammar, x, y int) *state {
+	s := &state{fresh: make(map[[3]int]int)}
+	index := make(map[int]int)
+	var copyRule func(x int) int
+	copyRule = func(x int) int {
+		if i, ok := index[x]; ok {
+			return i
+		}
+		body := slices.Clone(g.rules[x])
+		for k, it := range body {
+			if it.count == 0 {
+				body[k].sym = copyRule(it.sym)
+			} else {
+				s.next = max(s.next, it.sym+1)
+			}
+		}
+		index[x] = len(s.rules)
+		s.rules = append(s.rules, body)
+		return index[x]
+	}
+	x, y = copyRule(x), copyRule(y)
+	s.rules = append(s.rules, []item{{x, 0}}, []item{{y, 0}})
+	return s
+}
+
+func (s *state) equal() bool {
+	for {
+		lens := s.lengths()
+		x, y := len(s.rules)-2, len(s.rules)-1
+		if lens[x] != lens[y] {
+			return false
+		}
+		if lens[x] == 1 {
+			return s.firstLetter(x) == s.firstLetter(y)
+		}
+		s.compressBlocks()
+		s.compressPairs()
+	}
+}
+
+func (s *state) lengths() []int {
+	lens := make([]int, len(s.rules))
+	for i, body := range s.rules {
+		for _, it := range body {
+			if it.count > 0 {
+				lens[i] += it.count
+			} else {
+				lens[i] += lens[it.sym]
+			}
+		}
+	}
+	return lens
+}
+
+func (s *state) firstLetter(i int) int {
+	for s.rules[i][0].count == 0 {
+		i = s.rules[i][0].sym
+	}
+	return s.rules[i][0].sym
+}
+
+// the letter replacing a block or a pair
+func (s *state) letter(kind, a, b int) int {
+	key := [3]int{kind, a, b}
+	c, ok := s.fresh[key]
+	if !ok {
+		c = s.next
+		s.next++
+		s.fresh[key] = c
+	}
+	return c
+}
+
+// replaces every maximal block a^l with l > 1 by a fresh letter
+// Bottom up, each rule but the two words pops its first and last block into the rules using it, so afterwards
+// every rule starts and ends with letters different from the blocks next to it, and all maximal blocks are explicit
+func (s *state) compressBlocks() {
+	n := len(s.rules)
+	first, last := make([]item, n), make([]item, n) //the popped blocks, count 0 if none
+	for i, body := range s.rules {
+		out := make([]item, 0, len(body)+2)
+		push := func(it item) {
+			if k := len(out) - 1; it.count > 0 && k >= 0 && out[k].count > 0 && out[k].sym == it.sym {
+				out[k].count += it.count
+				return
+			}
+			out = append(out, it)
+		}
+		for _, it := range body {
+			if it.count > 0 {
+				push(it)
+				continue
+			}
+			if first[it.sym].count > 0 {
+				push(first[it.sym])
+			}
+			if len(s.rules[it.sym]) > 0 {
+				push(it)
+			}
+			if last[it.sym].count > 0 {
+				push(last[it.sym])
+			}
+		}
+		if i < n-2 && len(out) > 0 {
+			first[i], out = out[0], out[1:]
+			if len(out) > 0 {
+				last[i], out = out[len(out)-1], out[:len(out)-1]
+			}
+		}
+		s.rules[i] = out
+	}
+	for _, body := range s.rules {
+		for k, it := range body {
+			if it.count > 1 {
+				body[k] = item{s.letter(0, it.sym, it.count), 1}
+			}
+		}
+	}
+}
+
+// replaces the pairs ab with a in L and b in R by fresh letters, for a partition of the letters covering
+// at least a quarter of the pairs of different letters in the two words
+// After compressBlocks no two neighbouring letters are equal, so this always shortens the words
+// Bottom up, each rule but the two words pops its first letter if it is in R and its last if it is in L, which makes all these pairs explicit
+func (s *state) compressPairs() {
+	n := len(s.rules)
+	first, last := make([]int, n), make([]int, n)
+	end := func(it item, ends []int) int {
+		if it.count > 0 {
+			return it.sym
+		}
+		return ends[it.sym]
+	}
+	for i, body := range s.rules {
+		if len(body) > 0 {
+			first[i], last[i] = end(body[0], first), end(body[len(body)-1], last)
+		}
+	}
+	// the number of times each rule appears in the derivations of the two words, as a float since it is only a weight
+	uses := make([]float64, n)
+	uses[n-2], uses[n-1] = 1, 1
+	for i := n - 1; i >= 0; i-- {
+		for _, it := range s.rules[i] {
+			if it.count == 0 {
+				uses[it.sym] += uses[i]
+			}
+		}
+	}
+	weights := make(map[[2]int]float64)
+	for i, body := range s.rules {
+		for k := 1; k < len(body); k++ {
+			if a, b := end(body[k-1], last), end(body[k], first); a != b {
+				weights[[2]int{a, b}] += uses[i]
+			}
+		}
+	}
+	left := partition(weights)
+
+	popFirst, popLast := make([]int, n), make([]int, n) //the popped letters, -1 if none
+	for i, body := range s.rules {
+		out := make([]item, 0, len(body)+2)
+		for _, it := range body {
+			if it.count > 0 {
+				out = append(out, it)
+				continue
+			}
+			if popFirst[it.sym] >= 0 {
+				out = append(out, item{popFirst[it.sym], 1})
+			}
+			if len(s.rules[it.sym]) > 0 {
+				out = append(out, it)
+			}
+			if popLast[it.sym] >= 0 {
+				out = append(out, item{popLast[it.sym], 1})
+			}
+		}
+		popFirst[i], popLast[i] = -1, -1
+		if i < n-2 {
+			if len(out) > 0 && out[0].count > 0 && !left[out[0].sym] {
+				popFirst[i], out = out[0].sym, out[1:]
+			}
+			if k := len(out) - 1; k >= 0 && out[k].count > 0 && left[out[k].sym] {
+				popLast[i], out = out[k].sym, out[:k]
+			}
+		}
+		compressed := make([]item, 0, len(out))
+		for k := 0; k < len(out); k++ {
+			if it := out[k]; k+1 < len(out) && it.count > 0 && out[k+1].count > 0 && left[it.sym] && !left[out[k+1].sym] {
+				compressed = append(compressed, item{s.letter(1, it.sym, out[k+1].sym), 1})
+				k++
+			} else {
+				compressed = append(compressed, it)
+			}
+		}
+		s.rules[i] = compressed
+	}
+}
+
+// splits the letters into L (true) and R (false) greedily, so that at least half the weight of the pairs goes
+// between L and R, then orients it so that at least half of that goes from L to R
+func partition(weights map[[2]int]float64) map[int]bool {
+	neighbours := make(map[int][]int)
+	for p := range weights {
+		if _, ok := weights[[2]int{p[1], p[0]}]; ok && p[0] > p[1] {
+			continue //already added with the reverse pair
+		}
+		neighbours[p[0]] = append(neighbours[p[0]], p[1])
+		neighbours[p[1]] = append(neighbours[p[1]], p[0])
+	}
+	letters := make([]int, 0, len(neighbours))
+	for a := range neighbours {
+		letters = append(letters, a)
+	}
+	slices.Sort(letters) //map order is random, and the partition should not be
+	left, placed := make(map[int]bool), make(map[int]bool)
+	for _, a := range letters {
+		toLeft, toRight := 0.0, 0.0
+		for _, b := range neighbours[a] {
+			if !placed[b] {
+				continue
+			}
+			w := weights[[2]int{a, b}] + weights[[2]int{b, a}]
+			if left[b] {
+				toLeft += w
+			} else {
+				toRight += w
+			}
+		}
+		left[a], placed[a] = toLeft < toRight, true
+	}
+	forward, backward := 0.0, 0.0
+	for p, w := range weights {
+		switch {
+		case left[p[0]] && !left[p[1]]:
+			forward += w
+		case !left[p[0]] && left[p[1]]:
+			backward += w
+		}
+	}
+	if backward > forward {
+		for _, a := range letters {
+			left[a] = !left[a]
+		}
+	}
+	return left
+}
@@ -0,0 +1,74 @@
+package recompression_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/internal/recompression"
+)
+
+// random symbols of a grammar, along with the words they derive
+func randomSymbols(g *recompression.Grammar, rng *rand.Rand, letters, n int) ([]int, [][]int) {
+	syms, words := make([]int, 0, n), make([][]int, 0, n)
+	for range n {
+		switch k := len(syms); {
+		case k < 2 || rng.IntN(4) == 0:
+			a, c := rng.IntN(letters), rng.IntN(3)+1
+			syms = append(syms, g.Run(a, c))
+			words = append(words, slices.Repeat([]int{a}, c))
+		case rng.IntN(5) == 0:
+			i, c := rng.IntN(k), rng.IntN(4)+1
+			syms = append(syms, g.Pow(syms[i], c))
+			words = append(words, slices.Repeat(words[i], c))
+		default:
+			i, j := rng.IntN(k), rng.IntN(k)
+			syms = append(syms, g.Concat(syms[i], syms[j]))
+			words = append(words, slices.Concat(words[i], words[j]))
+		}
+	}
+	return syms, words
+}
+
+func TestEqualSmall(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 100 {
+		var g recompression.Grammar
+		syms, words := randomSymbols(&g, rng, 1+rng.IntN(3), 30)
+		for i := range syms {
+			for j := range syms {
+				if got, want := g.Equal(syms[i], syms[j]), slices.Equal(words[i], words[j]); got != want {
+					t.Fatalf("Equal(%v, %v) = %v, want %v", words[i], words[j], got, want)
+				}
+				if g.Len(syms[i]) != len(words[i]) {
+					t.Fatalf("Len of %v = %v", words[i], g.Len(syms[i]))
+				}
+			}
+		}
+	}
+}
+
+func TestEqualLarge(t *testing.T) {
+	const n = 100000000
+	var g recompression.Grammar
+	a, b, c := g.Run(0, 1), g.Run(1, 1), g.Run(2, 1)
+	ab, ba := g.Concat(a, b), g.Concat(b, a)
+	tests := []struct {
+		name string
+		u, v int
+		want bool
+	}{
+		{"different brackets", g.Pow(ab, n), g.Concat(g.Concat(a, g.Pow(ba, n-1)), b), true},
+		{"shifted", g.Concat(a, g.Pow(ba, n)), g.Concat(g.Pow(ab, n), b), false},
+		{"split powers", g.Pow(g.Pow(ab, 1000), n/1000), g.Concat(g.Pow(ab, n/2), g.Pow(ab, n/2)), true},
+		{"one letter off", g.Concat(g.Pow(ab, n), c), g.Concat(g.Concat(g.Pow(ab, n-1), g.Concat(b, a)), c), false},
+		{"huge runs", g.Concat(g.Run(0, n), g.Run(0, n)), g.Run(0, 2*n), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.Equal(tt.u, tt.v); got != tt.want {
+				t.Errorf("Equal = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
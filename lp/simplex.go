@@ -0,0 +1,196 @@
+package lp
+
+import (
+	"errors"
+	"math/big"
+)
+
+// A small exact linear programming solver over the rationals.
+// It is meant for the modest LPs that come up in group theory (e.g. scl computations), not for industrial sizes.
+// All arithmetic is done with math/big.Rat so the optimum is exact, which matters since scl and friends are rational invariants.
+
+var (
+	ErrInfeasible     = errors.New("lp: problem is infeasible")
+	ErrUnbounded      = errors.New("lp: problem is unbounded")
+	ErrInvalidProblem = errors.New("lp: constraint dimensions do not match")
+)
+
+// Maximize solves the standard form problem: maximize c·x subject to Ax = b and x >= 0.
+// A has one row per constraint and len(c) columns; b has one entry per row.
+// Returns an optimal x and the optimal value c·x.
+// We use the two-phase simplex method with Bland's rule, so cycling cannot occur.
+func Maximize(c []*big.Rat, A [][]*big.Rat, b []*big.Rat) ([]*big.Rat, *big.Rat, error) {
+	m, n := len(A), len(c)
+	if len(b) != m {
+		return nil, nil, ErrInvalidProblem
+	}
+	for _, row := range A {
+		if len(row) != n {
+			return nil, nil, ErrInvalidProblem
+		}
+	}
+
+	// tableau with columns: n structural variables, m artificial variables and the right hand side
+	t := &tableau{rows: make([][]*big.Rat, m), basis: make([]int, m), cols: n + m}
+	for i := range m {
+		row := make([]*big.Rat, n+m+1)
+		neg := b[i].Sign() < 0 //we want a nonnegative right hand side for the artificial basis
+		for j := range n {
+			row[j] = new(big.Rat)
+			if A[i][j] != nil {
+				row[j].Set(A[i][j])
+			}
+			if neg {
+				row[j].Neg(row[j])
+			}
+		}
+		for j := n; j < n+m; j++ {
+			row[j] = new(big.Rat)
+		}
+		row[n+i].SetInt64(1)
+		row[n+m] = new(big.Rat).Set(b[i])
+		if neg {
+			row[n+m].Neg(row[n+m])
+		}
+		t.rows[i] = row
+		t.basis[i] = n + i
+	}
+
+	// phase 1: minimize the sum of the artificial variables (i.e. maximize its negative)
+	phase1 := make([]*big.Rat, n+m)
+	for j := range n + m {
+		phase1[j] = new(big.Rat)
+		if j >= n {
+			phase1[j].SetInt64(-1)
+		}
+	}
+	if err := t.optimize(phase1, n+m); err != nil {
+		return nil, nil, err //phase 1 is bounded by 0 so this cannot happen, but we don't want to hide anything
+	}
+	if t.objective(phase1).Sign() != 0 {
+		return nil, nil, ErrInfeasible
+	}
+
+	// drive the remaining (zero-valued) artificial variables out of the basis
+	for i := 0; i < len(t.rows); i++ {
+		if t.basis[i] < n {
+			continue
+		}
+		pivoted := false
+		for j := range n {
+			if t.rows[i][j].Sign() != 0 {
+				t.pivot(i, j)
+				pivoted = true
+				break
+			}
+		}
+		if !pivoted { //redundant constraint, we drop it
+			t.rows = append(t.rows[:i], t.rows[i+1:]...)
+			t.basis = append(t.basis[:i], t.basis[i+1:]...)
+			i--
+		}
+	}
+
+	// phase 2: artificial columns are ignored from here on
+	phase2 := make([]*big.Rat, n+m)
+	for j := range n + m {
+		phase2[j] = new(big.Rat)
+		if j < n && c[j] != nil {
+			phase2[j].Set(c[j])
+		}
+	}
+	if err := t.optimize(phase2, n); err != nil {
+		return nil, nil, err
+	}
+
+	x := make([]*big.Rat, n)
+	for j := range x {
+		x[j] = new(big.Rat)
+	}
+	for i, j := range t.basis {
+		if j < n {
+			x[j].Set(t.rows[i][len(t.rows[i])-1])
+		}
+	}
+	return x, t.objective(phase2), nil
+}
+
+// dense simplex tableau, rows[i] has cols entries followed by the right hand side
+type tableau struct {
+	rows  [][]*big.Rat
+	basis []int //basis[i] is the variable that is basic in row i
+	cols  int
+}
+
+// value of the objective at the current basic solution
+func (t *tableau) objective(c []*big.Rat) *big.Rat {
+	val := new(big.Rat)
+	tmp := new(big.Rat)
+	for i, j := range t.basis {
+		val.Add(val, tmp.Mul(c[j], t.rows[i][t.cols]))
+	}
+	return val
+}
+
+// runs the simplex method maximizing c, only letting the first allowed columns enter the basis
+func (t *tableau) optimize(c []*big.Rat, allowed int) error {
+	reduced := new(big.Rat)
+	tmp := new(big.Rat)
+	for {
+		// Bland's rule: the entering variable is the smallest index with positive reduced cost
+		enter := -1
+		for j := range allowed {
+			reduced.Set(c[j])
+			for i, bj := range t.basis {
+				if t.rows[i][j].Sign() != 0 {
+					reduced.Sub(reduced, tmp.Mul(c[bj], t.rows[i][j]))
+				}
+			}
+			if reduced.Sign() > 0 {
+				enter = j
+				break
+			}
+		}
+		if enter < 0 {
+			return nil //optimal
+		}
+		// ratio test, ties broken by the smallest basic variable index (Bland again)
+		leave := -1
+		best := new(big.Rat)
+		for i := range t.rows {
+			if t.rows[i][enter].Sign() <= 0 {
+				continue
+			}
+			tmp.Quo(t.rows[i][t.cols], t.rows[i][enter])
+			if leave < 0 || tmp.Cmp(best) < 0 || (tmp.Cmp(best) == 0 && t.basis[i] < t.basis[leave]) {
+				leave = i
+				best.Set(tmp)
+			}
+		}
+		if leave < 0 {
+			return ErrUnbounded
+		}
+		t.pivot(leave, enter)
+	}
+}
+
+// pivots so that column j becomes basic in row r
+func (t *tableau) pivot(r, j int) {
+	inv := new(big.Rat).Inv(t.rows[r][j])
+	for k := range t.rows[r] {
+		t.rows[r][k].Mul(t.rows[r][k], inv)
+	}
+	tmp := new(big.Rat)
+	for i := range t.rows {
+		if i == r || t.rows[i][j].Sign() == 0 {
+			continue
+		}
+		factor := new(big.Rat).Set(t.rows[i][j])
+		for k := range t.rows[i] {
+			if t.rows[r][k].Sign() != 0 {
+				t.rows[i][k].Sub(t.rows[i][k], tmp.Mul(factor, t.rows[r][k]))
+			}
+		}
+	}
+	t.basis[r] = j
+}
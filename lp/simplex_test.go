@@ -0,0 +1,69 @@
+package lp_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/lp"
+)
+
+func rats(nums ...int64) []*big.Rat {
+	r := make([]*big.Rat, len(nums))
+	for i, x := range nums {
+		r[i] = big.NewRat(x, 1)
+	}
+	return r
+}
+
+func TestMaximize(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       []*big.Rat
+		A       [][]*big.Rat
+		b       []*big.Rat
+		want    *big.Rat
+		wantErr error
+	}{
+		{
+			// maximize x + y with x + 2y + s = 4, 3x + y + t = 6 (slacks s, t)
+			name: "bounded",
+			c:    rats(1, 1, 0, 0),
+			A:    [][]*big.Rat{rats(1, 2, 1, 0), rats(3, 1, 0, 1)},
+			b:    rats(4, 6),
+			want: big.NewRat(14, 5),
+		},
+		{
+			name:    "infeasible",
+			c:       rats(1, 1),
+			A:       [][]*big.Rat{rats(1, 1)},
+			b:       rats(-1),
+			wantErr: lp.ErrInfeasible,
+		},
+		{
+			name:    "unbounded",
+			c:       rats(1, 0),
+			A:       [][]*big.Rat{rats(1, -1)},
+			b:       rats(1),
+			wantErr: lp.ErrUnbounded,
+		},
+		{
+			name: "redundant constraint",
+			c:    rats(-1, -1),
+			A:    [][]*big.Rat{rats(1, 1), rats(2, 2)},
+			b:    rats(3, 6),
+			want: big.NewRat(-3, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := lp.Maximize(tt.c, tt.A, tt.b)
+			if err != tt.wantErr {
+				t.Fatalf("wanted error %v got error %v", tt.wantErr, err)
+			}
+			if err == nil && got.Cmp(tt.want) != 0 {
+				t.Fatalf("Maximize = %v want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package presentation
+
+// An Aho-Corasick automaton matches many patterns at once in a single left to right scan
+// Its states are the nodes of the trie of the patterns, i.e. all prefixes of patterns, with the root (state 0) being the empty prefix
+// The failure link of a state points to the state of its longest proper suffix that is also a prefix of some pattern
+// The dictionary link points to the nearest state along failure links at which a pattern ends, so we can list every pattern ending at a position
+// Letters are (generator, ±1) pairs, as returned by the At methods of this library
+
+type acState struct {
+	next  map[[2]int]int //trie edges
+	fail  int            //failure link
+	dict  int            //dictionary link, -1 if none
+	depth int            //length of the prefix this state stands for
+	rule  int            //index of the (first) pattern ending at this state, -1 if none
+}
+
+type acAutomaton struct {
+	states []acState
+}
+
+func newACState(depth int) acState {
+	return acState{next: make(map[[2]int]int), dict: -1, depth: depth, rule: -1}
+}
+
+// builds the automaton of the patterns, given as slices of letters
+// empty patterns are ignored, and when several patterns are equal the first one is kept
+func newACAutomaton(patterns [][][2]int) *acAutomaton {
+	a := &acAutomaton{states: []acState{newACState(0)}}
+	// build the trie
+	for i, p := range patterns {
+		if len(p) == 0 {
+			continue
+		}
+		s := 0
+		for _, x := range p {
+			t, ok := a.states[s].next[x]
+			if !ok {
+				t = len(a.states)
+				a.states = append(a.states, newACState(a.states[s].depth+1))
+				a.states[s].next[x] = t
+			}
+			s = t
+		}
+		if a.states[s].rule < 0 {
+			a.states[s].rule = i
+		}
+	}
+	// failure and dictionary links, breadth first so that shorter prefixes are done first
+	queue := make([]int, 0, len(a.states))
+	for _, t := range a.states[0].next {
+		queue = append(queue, t) //children of the root fail to the root
+	}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for x, t := range a.states[s].next {
+			f := a.step(a.states[s].fail, x)
+			a.states[t].fail = f
+			if a.states[f].rule >= 0 {
+				a.states[t].dict = f
+			} else {
+				a.states[t].dict = a.states[f].dict
+			}
+			queue = append(queue, t)
+		}
+	}
+	return a
+}
+
+// the state reached from s after reading x
+// this follows failure links, which costs at most the depth of s
+func (a *acAutomaton) step(s int, x [2]int) int {
+	for {
+		if t, ok := a.states[s].next[x]; ok {
+			return t
+		} else if s == 0 {
+			return 0
+		}
+		s = a.states[s].fail
+	}
+}
+
+// calls found on the index of every pattern ending at state s, longest first
+func (a *acAutomaton) matches(s int, found func(rule int)) {
+	if a.states[s].rule < 0 {
+		s = a.states[s].dict
+	}
+	for s >= 0 {
+		found(a.states[s].rule)
+		s = a.states[s].dict
+	}
+}
@@ -0,0 +1,455 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/fsa"
+)
+
+// Automatic structures for the shortlex order, computed as in KBMAG (Epstein, Holt and Rees)
+// Letters are ordered x_0 < x_0^-1 < x_1 < x_1^-1 < ..., which are the symbols 0, 1, 2, 3, ... of the fsa package
+//
+// The procedure:
+// 1. Knuth-Bendix on the monoid presentation gives rules u -> v, which are true equations in the group even when it doesn't finish
+// 2. The word differences of a rule are the elements u[:i]^-1 v[:i] (the shorter word being padded), written as words reduced by the rules
+// 3. The word difference automaton reads padded pairs (u, v), its states are the word differences and reading (a, b) from d leads to a^-1 d b
+// 4. The word acceptor W accepts the words without a subword u such that (u, v) reaches the difference 1 for some v < u in shortlex order
+// 5. The multiplier M_x accepts the pairs (u, v) of words of W reaching the difference x, so ux = v in the group
+// 6. We check the axioms: each M_x is total and functional on W, and the composites of the multipliers along the relators (including x x^-1 and x^-1 x) are the identity on W
+// Then W has exactly one word per element, its shortlex least representative
+// When an axiom fails we get two words equal in the group, add their word differences and try again, and if that doesn't help we run Knuth-Bendix longer
+
+var ErrNotAutomatic = errors.New("presentation: no shortlex automatic structure found within the rule budget")
+
+// A shortlex automatic structure on a group presentation, see ComputeAutomaticStructure
+// Treated as immutable
+type AutomaticStructure struct {
+	generators  int
+	acceptor    *fsa.DFA   //accepts the shortlex normal forms
+	multipliers []*fsa.DFA //multipliers[a] for the symbol a, and multipliers[2n] for the identity
+}
+
+// returns (a copy of) the word acceptor, which accepts exactly the shortlex normal forms, as words of symbols
+func (A *AutomaticStructure) Acceptor() *fsa.DFA {
+	return A.acceptor.Copy()
+}
+
+// returns (a copy of) the multiplier of the letter x, which accepts the padded pairs (u, v) of normal forms with ux = v in the group (see fsa.PairSymbol)
+// x = (g, 0) gives the identity multiplier, which accepts the pairs (u, u)
+func (A *AutomaticStructure) Multiplier(x [2]int) *fsa.DFA {
+	if x[1] == 0 {
+		return A.multipliers[len(A.multipliers)-1].Copy()
+	}
+	return A.multipliers[fsa.Symbol(x)].Copy()
+}
+
+// checks whether w is written in shortlex normal form
+func (A *AutomaticStructure) IsNormalForm(w RawWord) bool {
+	return A.acceptor.Accepts(fsa.Symbols(w))
+}
+
+// Reduces w to its shortlex normal form, one letter at a time with the multipliers
+// This takes O(|w|^2) time for a fixed structure
+// Precondition: the generators of w are in range
+func (A *AutomaticStructure) Reduce(w RawWord) RawWord {
+	u := make([]int, 0)
+	for _, a := range fsa.Symbols(w) {
+		u = A.multiply(u, a)
+	}
+	return RawWord(fsa.Letters(u))
+}
+
+func (A *AutomaticStructure) ReduceWord(w Word) Word {
+	return NewWord(A.Reduce(w.seq))
+}
+
+// checks whether u and v are equal in the group
+func (A *AutomaticStructure) Equal(u, v RawWord) bool {
+	return slices.Equal(fsa.Symbols(A.Reduce(u)), fsa.Symbols(A.Reduce(v)))
+}
+
+// the normal form of ua, for u a normal form
+// v has at most |u|+1 letters, so we run M_a on u padded by one letter, keeping every state reachable with some v and how we first got there
+func (A *AutomaticStructure) multiply(u []int, a int) []int {
+	M := A.multipliers[a]
+	k := fsa.GeneratorSymbols(A.generators)
+	pad := fsa.Padding(k)
+	// prev[i][s] is the state before s and the letter of v read to reach s after i pair symbols, or -1 if s isn't reachable then
+	prev := [][][2]int{newLayer(M.NumStates())}
+	prev[0][M.Start()] = [2]int{M.Start(), pad}
+	for i := 0; i <= len(u)+1; i++ {
+		if i >= len(u) {
+			for s := range prev[i] {
+				if prev[i][s][0] >= 0 && M.IsAccept(s) {
+					return backtrackMultiplier(prev, i, s, pad)
+				}
+			}
+		}
+		if i == len(u)+1 {
+			break
+		}
+		x := pad
+		if i < len(u) {
+			x = u[i]
+		}
+		next := newLayer(M.NumStates())
+		for s := range prev[i] {
+			if prev[i][s][0] < 0 {
+				continue
+			}
+			for y := range k + 1 {
+				if x == pad && y == pad {
+					continue
+				}
+				if t := M.Next(s, fsa.PairSymbol(x, y, k)); t >= 0 && next[t][0] < 0 {
+					next[t] = [2]int{s, y}
+				}
+			}
+		}
+		prev = append(prev, next)
+	}
+	panic("presentation: multiplier automaton is not total") //verified when the structure was computed
+}
+
+func newLayer(states int) [][2]int {
+	layer := make([][2]int, states)
+	for s := range layer {
+		layer[s] = [2]int{-1, -1}
+	}
+	return layer
+}
+
+func backtrackMultiplier(prev [][][2]int, i, s, pad int) []int {
+	v := make([]int, 0, i)
+	for ; i > 0; i-- {
+		if y := prev[i][s][1]; y != pad {
+			v = append(v, y)
+		}
+		s = prev[i][s][0]
+	}
+	slices.Reverse(v)
+	return v
+}
+
+// Computes a shortlex automatic structure for G, running Knuth-Bendix with at most maxRules rules
+// On success the structure is stored in G (see AutomaticStructure) and G gets the Automatic class, so Reduce uses it
+// Returns ErrNotAutomatic if no structure was found, which doesn't mean that G isn't automatic
+// Structures are only found for small presentations in practice, since the automata can get big
+func (G *GroupPresentation) ComputeAutomaticStructure(maxRules int) (*AutomaticStructure, error) {
+	A, err := G.computeAutomaticStructure(rulesBudget(context.Background(), maxRules))
+	if errors.Is(err, ErrRuleLimit) {
+		return nil, ErrNotAutomatic
+	}
+	return A, err
+}
+
+// ComputeAutomaticStructureContext is ComputeAutomaticStructure with at most limits.MaxRules rules whose left hand sides have at most limits.MaxWordLength letters
+// It returns ErrNotAutomatic when Knuth-Bendix finished without giving a structure, and ctx.Err() or a *LimitError when it stops first
+func (G *GroupPresentation) ComputeAutomaticStructureContext(ctx context.Context, limits Limits) (*AutomaticStructure, error) {
+	return G.computeAutomaticStructure(newBudget(ctx, limits))
+}
+
+// tries the rules found by Knuth-Bendix with budgets of rules doubling up to b's
+func (G *GroupPresentation) computeAutomaticStructure(b *budget) (*AutomaticStructure, error) {
+	ctx := b.ctx
+	order := monoidShortLex(G.gen)
+	R := NewRewritingSystem(G, order)
+	maxRules := b.limits.MaxRules
+	for rules := 32; ; rules *= 2 {
+		if maxRules >= 0 {
+			rules = min(rules, maxRules)
+		}
+		bRules := &budget{ctx: ctx, limits: b.limits}
+		bRules.limits.MaxRules = rules
+		S, confluent := R.knuthBendix(bRules, order)
+		if A := G.tryAutomaticStructure(ctx, S); A != nil {
+			G.mu.Lock()
+			defer G.mu.Unlock()
+			G.automatic = A
+			return A, G.addClassesLocked(automaticGroupClasses)
+		} else if err := ctx.Err(); err != nil {
+			return nil, err
+		} else if confluent {
+			return nil, ErrNotAutomatic //more rules won't help
+		} else if !errors.Is(bRules.err, ErrRuleLimit) || rules == maxRules {
+			return nil, bRules.err
+		}
+	}
+}
+
+// the shortlex order on the monoid presentation of a group on n generators where x_0 < X_0 < x_1 < X_1 < ...
+func monoidShortLex(n int) ShortLex {
+	letters := LetterOrder{Generators: make([]int, 0, 2*n)}
+	for i := range n {
+		letters.Generators = append(letters.Generators, i, n+i)
+	}
+	return ShortLex{Letters: letters}
+}
+
+// returns the automatic structure of G, or nil if it wasn't computed, see ComputeAutomaticStructure
+func (G *GroupPresentation) AutomaticStructure() *AutomaticStructure {
+	G.mu.RLock()
+	defer G.mu.RUnlock()
+	return G.automatic
+}
+
+// candidate structures tried for each rewriting system before asking Knuth-Bendix for more rules
+const maxAutomaticRounds = 200
+
+// builds and verifies candidate structures from the word differences of the rules of S, adding word differences at most maxAutomaticRounds times
+// or until ctx is done
+func (G *GroupPresentation) tryAutomaticStructure(ctx context.Context, S RewritingSystem) *AutomaticStructure {
+	rw, err := S.Compile(LeftmostFirst)
+	if err != nil {
+		return nil
+	}
+	b := newDifferences(G.gen, rw)
+	for i := range S.LHS {
+		u := symbolsOfMonoidWord(S.LHS[i], G.gen)
+		v := symbolsOfMonoidWord(S.RHS[i], G.gen)
+		b.add(u, v)
+	}
+	relators := make([][]int, 0)
+	for _, r := range G.MonoidPresentation().rel {
+		relators = append(relators, symbolsOfMonoidWord(r[0].seq, G.gen))
+	}
+	for i := 0; i < maxAutomaticRounds && ctx.Err() == nil; i++ {
+		A := b.candidate()
+		u, v, ok := A.verify(relators)
+		if ok {
+			return A
+		}
+		// u and v are equal in the group, and so are the words and their reductions by the rules
+		added := false
+		for _, pair := range [][2][]int{{u, v}, {v, u}, {u, b.reduce(u)}, {v, b.reduce(v)}} {
+			if b.add(pair[0], pair[1]) {
+				added = true
+			}
+		}
+		if !added {
+			return nil //the counterexample gives nothing new
+		}
+	}
+	return nil
+}
+
+// writes a positive word on the monoid generators x_i = i and X_i = n+i as symbols, without freely reducing it
+func symbolsOfMonoidWord(w RawWord, n int) []int {
+	symbols := make([]int, 0, len(w))
+	for _, u := range w {
+		a := 2 * u[0]
+		if u[0] >= n {
+			a = 2*(u[0]-n) + 1
+		}
+		for range u[1] {
+			symbols = append(symbols, a)
+		}
+	}
+	return symbols
+}
+
+// the word differences found so far
+type differences struct {
+	generators int
+	rewriter   *Rewriter
+	ids        map[string]int //WordID of a reduced word to its index
+	words      [][]int        //the differences as reduced words of symbols, words[0] is the identity
+}
+
+// starts with the identity and the letters, which we need for the multipliers
+func newDifferences(generators int, rw *Rewriter) *differences {
+	b := &differences{generators: generators, rewriter: rw, ids: make(map[string]int)}
+	b.index([]int{})
+	for a := range fsa.GeneratorSymbols(generators) {
+		b.index([]int{a})
+	}
+	return b
+}
+
+// reduces a word of symbols with the rules, through the monoid presentation
+func (b *differences) reduce(w []int) []int {
+	m := GroupToMonoidRawWord(RawWord(fsa.Letters(w)), b.generators)
+	return fsa.Symbols(MonoidToGroupRawWord(b.rewriter.Rewrite(m), b.generators))
+}
+
+// the index of the difference w, adding it if needed, and whether it is new
+func (b *differences) index(w []int) (int, bool) {
+	w = b.reduce(w)
+	id := WordID(fsa.Letters(w))
+	if i, ok := b.ids[id]; ok {
+		return i, false
+	}
+	b.ids[id] = len(b.words)
+	b.words = append(b.words, w)
+	return len(b.words) - 1, true
+}
+
+// the difference reached from the difference d after reading (x, y), where either may be padding
+func (b *differences) step(d, x, y int) []int {
+	pad := fsa.Padding(fsa.GeneratorSymbols(b.generators))
+	w := make([]int, 0, len(b.words[d])+2)
+	if x != pad {
+		w = append(w, fsa.InverseSymbol(x))
+	}
+	w = append(w, b.words[d]...)
+	if y != pad {
+		w = append(w, y)
+	}
+	return w
+}
+
+// adds the word differences of the pair (u, v) and reports whether there were new ones
+func (b *differences) add(u, v []int) bool {
+	k := fsa.GeneratorSymbols(b.generators)
+	added, d := false, 0
+	for _, p := range fsa.PadPair(u, v, k) {
+		x, y := fsa.SplitPairSymbol(p, k)
+		var isNew bool
+		d, isNew = b.index(b.step(d, x, y))
+		added = added || isNew
+	}
+	return added
+}
+
+// the word difference automaton, without accept states
+func (b *differences) automaton() *fsa.DFA {
+	k := fsa.GeneratorSymbols(b.generators)
+	pad := fsa.Padding(k)
+	D := fsa.NewDFA(fsa.PairSymbols(k))
+	for range len(b.words) - 1 {
+		D.AddState(false)
+	}
+	for d := range b.words {
+		for x := range k + 1 {
+			for y := range k + 1 {
+				if x == pad && y == pad {
+					continue
+				}
+				if t, ok := b.ids[WordID(fsa.Letters(b.reduce(b.step(d, x, y))))]; ok {
+					D.SetTransition(d, fsa.PairSymbol(x, y, k), t)
+				}
+			}
+		}
+	}
+	return D
+}
+
+// builds the word acceptor and the multipliers from the current differences, see the top of the file
+func (b *differences) candidate() *AutomaticStructure {
+	k := fsa.GeneratorSymbols(b.generators)
+	pad := fsa.Padding(k)
+	D := b.automaton()
+	// pairs (u, v) with u = v in the group and u > v: states are (difference, comparison so far) where the comparison is 0 (equal), 1 (u > v) or 2 (u < v)
+	reducible := fsa.NewDFA(fsa.PairSymbols(k))
+	for range 3*D.NumStates() - 1 {
+		reducible.AddState(false)
+	}
+	reducible.SetAccept(1, true)
+	for d := range D.NumStates() {
+		for p := range fsa.PairSymbols(k) {
+			t := D.Next(d, p)
+			if t < 0 {
+				continue
+			}
+			x, y := fsa.SplitPairSymbol(p, k)
+			for c := range 3 {
+				next := c
+				switch {
+				case y == pad: //u is longer
+					next = 1
+				case x == pad:
+					next = 2
+				case c == 0 && x > y:
+					next = 1
+				case c == 0 && x < y:
+					next = 2
+				}
+				reducible.SetTransition(3*d+c, p, 3*t+next)
+			}
+		}
+	}
+	all := fsa.NewDFA(k)
+	all.SetAccept(0, true)
+	for a := range k {
+		all.SetTransition(0, a, 0)
+	}
+	padded := fsa.Intersect(fsa.Lift(all, 0, k), fsa.Lift(all, 1, k))
+	lhs := fsa.Projection(fsa.Intersect(reducible, padded).Minimize(), 0, k).Minimize()
+	// words containing a left hand side, then the complement
+	contains := lhs.NFA()
+	before, after := contains.AddState(false), contains.AddState(true)
+	contains.AddStart(before)
+	contains.AddEpsilon(before, lhs.Start())
+	for s := range lhs.NumStates() {
+		if lhs.IsAccept(s) {
+			contains.SetAccept(s, false)
+			contains.AddEpsilon(s, after)
+		}
+	}
+	for a := range k {
+		contains.AddTransition(before, a, before)
+		contains.AddTransition(after, a, after)
+	}
+	A := &AutomaticStructure{generators: b.generators, acceptor: contains.Determinize().Complement().Minimize()}
+	onW := fsa.Intersect(fsa.Lift(A.acceptor, 0, k), fsa.Lift(A.acceptor, 1, k))
+	for a := range k + 1 {
+		target := 0 //the identity multiplier
+		if a < k {
+			target, _ = b.index([]int{a}) //already there
+		}
+		M := D.Copy()
+		M.SetAccept(target, true)
+		A.multipliers = append(A.multipliers, fsa.Intersect(M, onW).Minimize())
+	}
+	return A
+}
+
+// Checks the axioms, and returns two different normal forms equal in the group if one fails (or u and an equal word if M_x isn't total at u)
+// Since the acceptor is prefix closed and reading (a, a) from the identity difference, then ($, x), always leads back to the identity difference and then x,
+// the multipliers accept (u, ux) whenever u and ux are normal forms, so the checks below mean that the multipliers define an action of the group on the normal forms
+// in which the normal form of g is 1.g
+func (A *AutomaticStructure) verify(relators [][]int) ([]int, []int, bool) {
+	k := fsa.GeneratorSymbols(A.generators)
+	diagonal := fsa.Diagonal(k)
+	for a := range k {
+		M := A.multipliers[a]
+		if u, ok := fsa.Difference(A.acceptor, fsa.Projection(M, 0, k)).ShortestWord(); ok {
+			return u, append(slices.Clone(u), a), false
+		}
+		if u, v, ok := pairWitness(fsa.Difference(fsa.Compose(M, fsa.Swap(M, k), k), diagonal), k); ok {
+			return u, v, false
+		}
+	}
+	for _, r := range relators {
+		C := A.multipliers[k]
+		for _, a := range r {
+			C = fsa.Compose(C, A.multipliers[a], k).Minimize()
+		}
+		if u, v, ok := pairWitness(fsa.Difference(C, diagonal), k); ok {
+			return u, v, false
+		}
+	}
+	return nil, nil, true
+}
+
+// a pair of words accepted by d, if any
+func pairWitness(d *fsa.DFA, k int) ([]int, []int, bool) {
+	w, ok := d.ShortestWord()
+	if !ok {
+		return nil, nil, false
+	}
+	pad := fsa.Padding(k)
+	u, v := make([]int, 0, len(w)), make([]int, 0, len(w))
+	for _, p := range w {
+		x, y := fsa.SplitPairSymbol(p, k)
+		if x != pad {
+			u = append(u, x)
+		}
+		if y != pad {
+			v = append(v, y)
+		}
+	}
+	return u, v, true
+}
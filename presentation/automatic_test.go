@@ -0,0 +1,152 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/fsa"
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestComputeAutomaticStructure(t *testing.T) {
+	tests := []struct {
+		name    string
+		gen     int
+		rel     []RawWord
+		spheres []int64 //number of normal forms of each length
+		reduces [][2]RawWord
+	}{
+		{
+			name:    "free group",
+			gen:     2,
+			spheres: []int64{1, 4, 12, 36, 108},
+			reduces: [][2]RawWord{{{{0, 1}, {1, 1}, {1, -1}}, {{0, 1}}}},
+		},
+		{
+			name:    "Z^2",
+			gen:     2,
+			rel:     []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -1}}},
+			spheres: []int64{1, 4, 8, 12, 16},
+			reduces: [][2]RawWord{{{{1, 2}, {0, -1}, {1, -1}}, {{0, -1}, {1, 1}}}},
+		},
+		{
+			name:    "Z/3",
+			gen:     1,
+			rel:     []RawWord{{{0, 3}}},
+			spheres: []int64{1, 2, 0},
+			reduces: [][2]RawWord{{{{0, 2}}, {{0, -1}}}, {{{0, 5}}, {{0, -1}}}},
+		},
+		{
+			name:    "S3",
+			gen:     2,
+			rel:     []RawWord{{{0, 2}}, {{1, 3}}, {{0, 1}, {1, 1}, {0, 1}, {1, 1}}},
+			spheres: []int64{1, 3, 2, 0},
+			reduces: [][2]RawWord{{{{1, 1}, {0, 1}}, {{0, 1}, {1, -1}}}, {{{0, -1}, {1, 2}}, {{0, 1}, {1, -1}}}},
+		},
+		{
+			name:    "trivial group",
+			gen:     1,
+			rel:     []RawWord{{{0, 1}}},
+			spheres: []int64{1, 0, 0},
+			reduces: [][2]RawWord{{{{0, 3}}, {}}},
+		},
+		{
+			name:    "trivial group on two generators",
+			gen:     2,
+			rel:     []RawWord{{{0, 1}}, {{1, 1}}},
+			spheres: []int64{1, 0, 0},
+			reduces: [][2]RawWord{{{{0, 1}, {1, -2}}, {}}},
+		},
+		{
+			name:    "trefoil knot group",
+			gen:     2,
+			rel:     []RawWord{{{0, 1}, {1, 1}, {0, 1}, {1, -1}, {0, -1}, {1, -1}}},
+			reduces: [][2]RawWord{{{{1, 1}, {0, 1}, {1, 1}}, {{0, 1}, {1, 1}, {0, 1}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet(wordsOf(tt.rel)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			A, err := G.ComputeAutomaticStructure(200)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !G.Classes()[p.Automatic] || G.AutomaticStructure() != A {
+				t.Errorf("structure not stored in the presentation")
+			}
+			if tt.spheres != nil {
+				counts := A.Acceptor().CountByLength(len(tt.spheres) - 1)
+				for i, c := range counts {
+					if c.Int64() != tt.spheres[i] {
+						t.Errorf("%v normal forms of length %v, want %v", c, i, tt.spheres[i])
+					}
+				}
+			}
+			for _, r := range tt.reduces {
+				got := A.Reduce(r[0])
+				if !p.EqualRawWord(got, r[1]) || !A.IsNormalForm(got) {
+					t.Errorf("Reduce(%v) = %v, want %v", r[0], got, r[1])
+				}
+				if gotG, _ := G.Reduce(p.NewWord(r[0])); !p.EqualWord(gotG, p.NewWord(got)) {
+					t.Errorf("G.Reduce(%v) = %v, want %v", r[0], gotG, got)
+				}
+			}
+			// inserting relators or cancelling pairs anywhere doesn't change the normal form
+			rng := rand.New(rand.NewPCG(1, uint64(tt.gen)))
+			for range 50 {
+				w := randomRawWord(rng, tt.gen, 8)
+				x := rng.IntN(tt.gen)
+				v := append(append(append(RawWord{}, w[:len(w)/2]...), [2]int{x, 1}, [2]int{x, -1}), w[len(w)/2:]...)
+				if len(tt.rel) > 0 {
+					v = append(v, tt.rel[rng.IntN(len(tt.rel))]...)
+				}
+				if !A.Equal(w, v) {
+					t.Errorf("%v and %v have different normal forms %v and %v", w, v, A.Reduce(w), A.Reduce(v))
+				}
+				if !A.IsNormalForm(A.Reduce(w)) {
+					t.Errorf("Reduce(%v) = %v is not accepted", w, A.Reduce(w))
+				}
+			}
+		})
+	}
+}
+
+func TestMultiplier(t *testing.T) {
+	G, _ := p.NewFreeAbelianGroup(2)
+	A, err := G.ComputeAutomaticStructure(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := fsa.Symbol([2]int{0, 1}), fsa.Symbol([2]int{1, 1})
+	M := A.Multiplier([2]int{0, 1})
+	// the normal form of ba is ab
+	if !M.AcceptsPair([]int{b}, []int{a, b}, 4) || M.AcceptsPair([]int{b}, []int{b, a}, 4) {
+		t.Errorf("wrong multiplier for a")
+	}
+	if !A.Multiplier([2]int{0, 0}).AcceptsPair([]int{a, b}, []int{a, b}, 4) {
+		t.Errorf("identity multiplier rejects (ab, ab)")
+	}
+}
+
+func randomRawWord(rng *rand.Rand, generators, maxLen int) RawWord {
+	w := make(RawWord, rng.IntN(maxLen+1))
+	for i := range w {
+		w[i] = [2]int{rng.IntN(generators), 2*rng.IntN(2) - 1}
+	}
+	return w
+}
+
+func TestComputeAutomaticStructureFails(t *testing.T) {
+	// the Baumslag-Solitar group BS(1, 2) is not automatic
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}})}))
+	if _, err := G.ComputeAutomaticStructure(20); err != p.ErrNotAutomatic {
+		t.Errorf("got error %v, want ErrNotAutomatic", err)
+	}
+	if G.AutomaticStructure() != nil || G.Classes()[p.Automatic] {
+		t.Errorf("failed computation changed the presentation")
+	}
+}
@@ -0,0 +1,85 @@
+package presentation
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// ReduceAll is ReduceAllWorkers with one worker per CPU
+func (G *GroupPresentation) ReduceAll(ctx context.Context, words iter.Seq[Word]) iter.Seq2[Word, error] {
+	return G.ReduceAllWorkers(ctx, words, runtime.GOMAXPROCS(0))
+}
+
+// ReduceAllWorkers yields Reduce(w) for the words in order, reducing up to workers words at a time in their own goroutines
+// The solver is picked once for all the words, which share its precomputed data (rewriting systems, Magnus breakdown, automatic structure...)
+// An invalid word yields its error and the iteration goes on, and when ctx is done the iteration yields ctx.Err() once and stops
+// At most 2*workers words are read ahead, and all goroutines have stopped when the iteration ends, even if the loop breaks early
+func (G *GroupPresentation) ReduceAllWorkers(ctx context.Context, words iter.Seq[Word], workers int) iter.Seq2[Word, error] {
+	return func(yield func(Word, error) bool) {
+		workers = max(workers, 1)
+		s := G.solver()
+		type result struct {
+			w   Word
+			err error
+		}
+		type job struct {
+			w      Word
+			result chan result
+		}
+		innerCtx, cancel := context.WithCancel(ctx)
+		jobs := make(chan job)
+		pending := make(chan chan result, 2*workers) //the results in the order of the words
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+		wg.Add(1)
+		go func() { //reads the words
+			defer wg.Done()
+			defer close(jobs)
+			defer close(pending)
+			for w := range words {
+				j := job{w: w, result: make(chan result, 1)}
+				select {
+				case pending <- j.result:
+				case <-innerCtx.Done():
+					return
+				}
+				select {
+				case jobs <- j:
+				case <-innerCtx.Done():
+					return
+				}
+			}
+		}()
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					r, err := G.reduceWith(innerCtx, s, j.w, Limits{})
+					j.result <- result{r, err}
+				}
+			}()
+		}
+		for next := range pending {
+			var r result
+			select {
+			case r = <-next:
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				yield(EmptyWord(), err)
+				return
+			} else if !yield(r.w, r.err) {
+				return
+			}
+		}
+		if err := ctx.Err(); err != nil { //the words were cut short
+			yield(EmptyWord(), err)
+		}
+	}
+}
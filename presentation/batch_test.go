@@ -0,0 +1,54 @@
+package presentation_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestReduceAll(t *testing.T) {
+	bs := RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}}
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(bs)}))
+	rng := rand.New(rand.NewPCG(17, 18))
+	words := make([]p.Word, 500)
+	for i := range words {
+		words[i] = p.NewWord(p.ConcatRawWord(productOfConjugates(rng, bs, 2), randomRawWord(rng, 2, 3)))
+	}
+	words[100] = p.NewWord(RawWord{{2, 1}}) //invalid
+	for _, workers := range []int{1, 3, 16} {
+		i := 0
+		for r, err := range G.ReduceAllWorkers(context.Background(), slices.Values(words), workers) {
+			want, wantErr := G.Reduce(words[i])
+			if (err != nil) != (wantErr != nil) || !p.EqualWord(r, want) {
+				t.Errorf("%d workers: word %d reduces to %v, %v, want %v, %v", workers, i, r, err, want, wantErr)
+			}
+			i++
+		}
+		if i != len(words) {
+			t.Errorf("%d workers: got %d results, want %d", workers, i, len(words))
+		}
+	}
+
+	// breaking early stops the workers
+	n := 0
+	for range G.ReduceAll(context.Background(), slices.Values(words)) {
+		if n++; n == 10 {
+			break
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := 0
+	for _, err := range G.ReduceAll(ctx, slices.Values(words)) {
+		if results++; err != context.Canceled {
+			t.Errorf("got error %v, want context.Canceled", err)
+		}
+	}
+	if results != 1 {
+		t.Errorf("got %d results after cancellation, want 1", results)
+	}
+}
@@ -0,0 +1,118 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+)
+
+// Budgets of long computations: the word problem is undecidable in general, so completion, coset enumeration and the searches built on them may run forever
+// Their Context variants (ReduceContext, KnuthBendixContext, CertifyContext, ...) stop when the context is done, returning ctx.Err(),
+// or when a budget of their Limits runs out, returning a *LimitError saying which one
+
+// A resource a computation can run out of
+type Budget int
+
+const (
+	RuleBudget       Budget = iota //rules added by Knuth-Bendix completion
+	CosetBudget                    //cosets defined by Todd-Coxeter enumeration
+	WordLengthBudget               //length in letters of the words along the way
+)
+
+func (b Budget) String() string {
+	switch b {
+	case RuleBudget:
+		return "rule"
+	case CosetBudget:
+		return "coset"
+	case WordLengthBudget:
+		return "word length"
+	}
+	return "unknown"
+}
+
+// Limits of a computation, where zero means no limit, so that only the context stops it
+// Each computation only uses the limits that make sense for it
+type Limits struct {
+	MaxRules      int
+	MaxCosets     int
+	MaxWordLength int
+}
+
+// The Budget ran out, Limit being its value
+type LimitError struct {
+	Budget Budget
+	Limit  int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("presentation: %v budget of %d ran out", e.Budget, e.Limit)
+}
+
+// errors.Is(err, ErrRuleLimit) and so on tell which budget ran out whatever the limit
+func (e *LimitError) Is(target error) bool {
+	t, ok := target.(*LimitError)
+	return ok && t.Budget == e.Budget && (t.Limit == 0 || t.Limit == e.Limit)
+}
+
+var (
+	ErrRuleLimit       error = &LimitError{Budget: RuleBudget}
+	ErrCosetLimit      error = &LimitError{Budget: CosetBudget}
+	ErrWordLengthLimit error = &LimitError{Budget: WordLengthBudget}
+)
+
+// a running computation, whose limits are negative when there is none, and why it stopped
+// a nil budget never stops
+type budget struct {
+	ctx    context.Context
+	limits Limits
+	rules  int //added so far
+	err    error
+}
+
+// the budget of limits, where zero means no limit
+func newBudget(ctx context.Context, limits Limits) *budget {
+	for _, l := range []*int{&limits.MaxRules, &limits.MaxCosets, &limits.MaxWordLength} {
+		if *l == 0 {
+			*l = -1
+		}
+	}
+	return &budget{ctx: ctx, limits: limits}
+}
+
+// the budget of at most maxRules rules (none if negative) and nothing else
+func rulesBudget(ctx context.Context, maxRules int) *budget {
+	return &budget{ctx: ctx, limits: Limits{MaxRules: maxRules, MaxCosets: -1, MaxWordLength: -1}}
+}
+
+// reports whether the computation must stop at w, because ctx is done or w is too long, and records why
+func (b *budget) stopped(w RawWord) bool {
+	if b == nil {
+		return false
+	} else if b.err != nil {
+		return true
+	} else if err := b.ctx.Err(); err != nil {
+		b.err = err
+	} else if b.limits.MaxWordLength >= 0 && letterLen(w) > b.limits.MaxWordLength {
+		b.err = &LimitError{Budget: WordLengthBudget, Limit: b.limits.MaxWordLength}
+	}
+	return b.err != nil
+}
+
+// counts a new rule, reporting whether there was room for it
+func (b *budget) addRule() bool {
+	if b.limits.MaxRules >= 0 && b.rules >= b.limits.MaxRules {
+		b.err = &LimitError{Budget: RuleBudget, Limit: b.limits.MaxRules}
+		return false
+	}
+	b.rules++
+	return true
+}
+
+// the number of letters of w
+func letterLen(w RawWord) int {
+	n := 0
+	for _, u := range w {
+		n += abs(u[1])
+	}
+	return n
+}
@@ -0,0 +1,87 @@
+package presentation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestKnuthBendixContext(t *testing.T) {
+	// completion never finishes for BS(1, 2) with shortlex
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}})}))
+	order := p.ShortLex{Letters: p.LetterOrder{Generators: []int{0, 2, 1, 3}}}
+	R := p.NewRewritingSystem(G, order)
+	_, confluent, err := R.KnuthBendixContext(context.Background(), order, p.Limits{MaxRules: 50})
+	var limit *p.LimitError
+	if confluent || !errors.As(err, &limit) || limit.Budget != p.RuleBudget || limit.Limit != 50 || !errors.Is(err, p.ErrRuleLimit) {
+		t.Errorf("got %v, %v, want the rule budget of 50 to run out", confluent, err)
+	}
+	if _, _, err := R.KnuthBendixContext(context.Background(), order, p.Limits{MaxWordLength: 6}); !errors.Is(err, p.ErrWordLengthLimit) {
+		t.Errorf("got error %v, want ErrWordLengthLimit", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := R.KnuthBendixContext(ctx, order, p.Limits{}); err != context.Canceled {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+
+	// Z^2 completes within the limits
+	Z2, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}})}))
+	if _, confluent, err := p.NewRewritingSystem(Z2, order).KnuthBendixContext(context.Background(), order, p.Limits{MaxRules: 50}); !confluent || err != nil {
+		t.Errorf("got %v, %v, want a confluent system", confluent, err)
+	}
+}
+
+func TestReduceContext(t *testing.T) {
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}})}))
+	w := p.NewWord(RawWord{{1, 2}, {0, 1}, {1, -2}, {0, -4}}) //b^2 a b^-2 = a^4
+	if r, err := G.ReduceContext(context.Background(), w, p.Limits{}); err != nil || r.Len() != 0 {
+		t.Errorf("got %v, %v, want the empty word", r, err)
+	}
+	if _, err := G.ReduceContext(context.Background(), w, p.Limits{MaxWordLength: 8}); !errors.Is(err, p.ErrWordLengthLimit) {
+		t.Errorf("got error %v, want ErrWordLengthLimit", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := G.ReduceContext(ctx, w, p.Limits{}); err != context.Canceled {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if _, err := G.IsTrivialOneRelatorContext(ctx, w, p.Limits{}); err != context.Canceled {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if _, _, err := G.MagnusSubgroupMemberContext(ctx, []int{0}, w, p.Limits{}); err != context.Canceled {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestCertifyContext(t *testing.T) {
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}})}))
+	a, b := p.NewWord(RawWord{{0, 1}}), p.NewWord(RawWord{{1, 1}})
+	if _, err := G.CertifyContext(context.Background(), a, b, p.Limits{MaxRules: 40}); !errors.Is(err, p.ErrRuleLimit) {
+		t.Errorf("got error %v, want ErrRuleLimit", err)
+	}
+	if _, err := G.ComputeAutomaticStructureContext(context.Background(), p.Limits{MaxRules: 20}); !errors.Is(err, p.ErrRuleLimit) {
+		t.Errorf("got error %v, want ErrRuleLimit", err)
+	}
+	Z2, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}})}))
+	if _, err := Z2.CertifyContext(context.Background(), a, b, p.Limits{}); err != p.ErrNoCertificate {
+		t.Errorf("got error %v, want ErrNoCertificate", err)
+	}
+}
+
+func TestDecideContext(t *testing.T) {
+	// Z^2 with a redundant relator, where no complete solver applies
+	commutator := RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}}
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet(wordsOf([]RawWord{commutator, p.ConcatRawWord(commutator, commutator)})))
+	u := p.NewWord(RawWord{{0, 5}, {1, 5}})
+	v := p.NewWord(RawWord{{1, 5}, {0, 5}})
+	got, err := G.DecideContext(context.Background(), u, v, p.Limits{MaxRules: 1, MaxCosets: 100})
+	if got != p.Unknown || !errors.Is(err, p.ErrRuleLimit) || !errors.Is(err, p.ErrCosetLimit) {
+		t.Errorf("got %v, %v, want unknown with both budgets run out", got, err)
+	}
+	if got, err := G.DecideContext(context.Background(), u, v, p.Limits{MaxRules: 100, MaxCosets: 100}); got != p.Equal || err != nil {
+		t.Errorf("got %v, %v, want equal", got, err)
+	}
+}
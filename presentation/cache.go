@@ -0,0 +1,85 @@
+package presentation
+
+import (
+	"container/list"
+	"sync"
+)
+
+// An optional bounded cache of reductions, keyed by the hash of the input word, see EnableReductionCache
+// Repeated Mu and Equal on the same elements (e.g. in a breadth first search of the Cayley graph) then only reduce each word once
+// Least recently used words are evicted first
+
+// Statistics of a reduction cache
+type CacheStats struct {
+	Hits, Misses uint64
+	Len          int //words cached
+	Capacity     int
+}
+
+type reductionCache struct {
+	mu           sync.Mutex
+	capacity     int
+	order        *list.List               //of *cacheEntry, most recently used first
+	entries      map[uint64]*list.Element //by hash, holding a single word per hash
+	hits, misses uint64
+}
+
+type cacheEntry struct {
+	word    Word
+	solver  string //the name of the solver that reduced it, since the solver picked for G can change, e.g. with AddClass
+	reduced Word
+}
+
+func newReductionCache(capacity int) *reductionCache {
+	return &reductionCache{capacity: capacity, order: list.New(), entries: make(map[uint64]*list.Element)}
+}
+
+func (c *reductionCache) get(w Word, solver string) (Word, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[w.hash]; ok && e.Value.(*cacheEntry).solver == solver && EqualWord(e.Value.(*cacheEntry).word, w) {
+		c.order.MoveToFront(e)
+		c.hits++
+		return e.Value.(*cacheEntry).reduced, true
+	}
+	c.misses++
+	return EmptyWord(), false
+}
+
+func (c *reductionCache) put(w Word, solver string, reduced Word) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[w.hash]; ok { //the same word, or a rare collision which we simply replace
+		e.Value = &cacheEntry{word: w, solver: solver, reduced: reduced}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[w.hash] = c.order.PushFront(&cacheEntry{word: w, solver: solver, reduced: reduced})
+	if c.order.Len() > c.capacity {
+		delete(c.entries, c.order.Remove(c.order.Back()).(*cacheEntry).word.hash)
+	}
+}
+
+func (c *reductionCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Len: c.order.Len(), Capacity: c.capacity}
+}
+
+// EnableReductionCache makes Reduce (and so Mu, Equal, ReduceAll...) remember the reductions of the last capacity words it reduced
+// A capacity of 0 or less removes the cache, and replacing the cache resets its statistics
+func (G *GroupPresentation) EnableReductionCache(capacity int) {
+	if capacity <= 0 {
+		G.cache.Store(nil)
+		return
+	}
+	G.cache.Store(newReductionCache(capacity))
+}
+
+// ReductionCacheStats returns the statistics of the reduction cache, all zero if there is none
+func (G *GroupPresentation) ReductionCacheStats() CacheStats {
+	if c := G.cache.Load(); c != nil {
+		return c.stats()
+	}
+	return CacheStats{}
+}
@@ -0,0 +1,79 @@
+package presentation_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestReductionCache(t *testing.T) {
+	rel := p.NewWordSet([]p.Word{p.NewWord(RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}})})
+	G, _ := p.NewGroupPresentation(2, rel)
+	fresh, _ := p.NewGroupPresentation(2, rel)
+	if s := G.ReductionCacheStats(); s != (p.CacheStats{}) {
+		t.Errorf("got %+v without a cache, want zero", s)
+	}
+	G.EnableReductionCache(2)
+	u, v, w := p.NewWord(RawWord{{1, 1}, {0, 1}}), p.NewWord(RawWord{{0, 2}}), p.NewWord(RawWord{{1, -1}})
+	for _, x := range []p.Word{u, u, v, u, w, v} { //w evicts v, the least recently used
+		r, err := G.Reduce(x)
+		wantR, _ := fresh.Reduce(x)
+		if err != nil || !p.EqualWord(r, wantR) {
+			t.Errorf("Reduce(%v) = %v, %v, want %v", x, r, err, wantR)
+		}
+	}
+	if s := G.ReductionCacheStats(); s != (p.CacheStats{Hits: 2, Misses: 4, Len: 2, Capacity: 2}) {
+		t.Errorf("got %+v", s)
+	}
+
+	// invalid words are not cached
+	bad := p.NewWord(RawWord{{2, 1}})
+	for range 2 {
+		if _, err := G.Reduce(bad); err == nil {
+			t.Errorf("Reduce(%v) has no error", bad)
+		}
+	}
+	if s := G.ReductionCacheStats(); s.Hits != 2 || s.Misses != 6 {
+		t.Errorf("got %+v after invalid words", s)
+	}
+
+	G.EnableReductionCache(0)
+	if s := G.ReductionCacheStats(); s != (p.CacheStats{}) {
+		t.Errorf("got %+v after disabling the cache, want zero", s)
+	}
+}
+
+func TestReductionCacheConcurrently(t *testing.T) {
+	bs := RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}}
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(bs)}))
+	rng := rand.New(rand.NewPCG(19, 20))
+	words := make([]p.Word, 50)
+	want := make([]p.Word, len(words))
+	for i := range words {
+		words[i] = p.NewWord(p.ConcatRawWord(productOfConjugates(rng, bs, 2), randomRawWord(rng, 2, 3)))
+		want[i], _ = G.Reduce(words[i])
+	}
+	G.EnableReductionCache(len(words) / 2)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			i := 0
+			for r, err := range G.ReduceAllWorkers(context.Background(), slices.Values(words), 2) {
+				if err != nil || !p.EqualWord(r, want[i]) {
+					t.Errorf("word %d reduces to %v, %v, want %v", i, r, err, want[i])
+				}
+				i++
+			}
+		}()
+	}
+	wg.Wait()
+	if s := G.ReductionCacheStats(); s.Hits+s.Misses != uint64(4*len(words)) || s.Len != len(words)/2 {
+		t.Errorf("got %+v", s)
+	}
+}
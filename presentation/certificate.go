@@ -0,0 +1,216 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+)
+
+// Certificates of equality: u = v in G exactly when u v^-1 is freely equal to a product of conjugates of relators and their inverses
+// Such a product is a van Kampen derivation, and anyone can check it with free reduction alone, without trusting how it was found
+// We find them by keeping track of proofs while rewriting: if the rule l -> r comes with a product P freely equal to l r^-1,
+// then rewriting x l y to x r y comes with x P x^-1, and the proofs of the steps multiply
+
+var (
+	ErrNoCertificate      = errors.New("presentation: no certificate found")
+	ErrInvalidCertificate = errors.New("presentation: invalid certificate")
+)
+
+// The factor c^-1 r^e c of a certificate
+type ConjugatedRelator struct {
+	Relator    Word //a relator of G
+	Exponent   int  //1 or -1
+	Conjugator Word //c
+}
+
+// the factor as a word, c^-1 r^e c
+func (f ConjugatedRelator) rawWord() RawWord {
+	r := f.Relator.seq
+	if f.Exponent < 0 {
+		r = InvRawWord(r)
+	}
+	return ConjugateRawWord(r, f.Conjugator.seq)
+}
+
+// A certificate that U = V, where U V^-1 is freely equal to the product of the factors
+type Certificate struct {
+	U, V    Word
+	Factors []ConjugatedRelator
+}
+
+// a product of conjugates of relators, kept along rewriting
+type proof []ConjugatedRelator
+
+// the product of the inverses of the factors in reverse order
+func (p proof) inverse() proof {
+	q := make(proof, len(p))
+	for i, f := range p {
+		q[len(p)-1-i] = ConjugatedRelator{Relator: f.Relator, Exponent: -f.Exponent, Conjugator: f.Conjugator}
+	}
+	return q
+}
+
+func (p proof) concat(q proof) proof {
+	return append(append(proof{}, p...), q...)
+}
+
+// x p x^-1, where x c^-1 r c x^-1 = (c x^-1)^-1 r (c x^-1)
+func (p proof) conjugate(x RawWord) proof {
+	q := make(proof, len(p))
+	xInv := InvRawWord(x)
+	for i, f := range p {
+		q[i] = ConjugatedRelator{Relator: f.Relator, Exponent: f.Exponent, Conjugator: NewWord(ReduceRawWord(ConcatRawWord(f.Conjugator.seq, xInv)))}
+	}
+	return q
+}
+
+// rewrites with rules coming with proofs, on group words or on positive words of the monoid presentation
+type provingRewriter struct {
+	automaton *acAutomaton
+	rules     []kbRule
+	group     func(RawWord) RawWord //the group word of a word being rewritten
+}
+
+func newProvingRewriter(rules []kbRule, group func(RawWord) RawWord) *provingRewriter {
+	patterns := make([][][2]int, len(rules))
+	for i, r := range rules {
+		patterns[i] = r.lhs
+	}
+	return &provingRewriter{automaton: newACAutomaton(patterns), rules: rules, group: group}
+}
+
+// freely reduces w and rewrites it until no left hand side occurs in it, returning the result and a proof of w result^-1
+// this rescans from the start after each rule, so it is slower than Rewriter
+func (pr *provingRewriter) rewrite(w RawWord) (RawWord, proof) {
+	w = expandRawWord(ReduceRawWord(w))
+	p := proof{}
+	for {
+		s, rule, end := 0, -1, 0
+		for k, x := range w {
+			s = pr.automaton.step(s, x)
+			pr.automaton.matches(s, func(i int) {
+				if rule < 0 {
+					rule, end = i, k+1
+				}
+			})
+			if rule >= 0 {
+				break
+			}
+		}
+		if rule < 0 {
+			return w, p
+		}
+		r := pr.rules[rule]
+		prefix := w[:end-len(r.lhs)]
+		p = p.concat(r.proof.conjugate(pr.group(prefix)))
+		w = expandRawWord(ReduceRawWord(ConcatRawWord(ConcatRawWord(prefix, r.rhs), w[end:])))
+	}
+}
+
+// the rules of Dehn's algorithm (see DehnReduce), u -> v^-1 for the cyclic conjugates u v of relators and their inverses
+// with |u| > |v|, each proved by its cyclic conjugate
+func (G *GroupPresentation) provenDehnRules() []kbRule {
+	rules := make([]kbRule, 0)
+	for _, r := range G.sortedRelators() { //relators are cyclically reduced, see CyclicWord
+		for _, e := range []int{1, -1} {
+			s := expandRawWord(r.seq)
+			if e < 0 {
+				s = expandRawWord(InvRawWord(r.seq))
+			}
+			k := len(s)/2 + 1
+			for i := range s {
+				conj := append(append(RawWord{}, s[i:]...), s[:i]...) //s[:i]^-1 s s[:i]
+				rules = append(rules, kbRule{
+					lhs:   conj[:k],
+					rhs:   expandRawWord(InvRawWord(conj[k:])),
+					proof: proof{{Relator: r, Exponent: e, Conjugator: NewWord(ReduceRawWord(s[:i]))}},
+				})
+			}
+		}
+	}
+	return rules
+}
+
+// Knuth-Bendix on the monoid presentation of G keeping track of proofs until b stops it, see MonoidPresentation
+func (G *GroupPresentation) provenKnuthBendix(b *budget) (*knuthBendix, bool) {
+	n := G.gen
+	kb := &knuthBendix{order: monoidShortLex(n), group: func(w RawWord) RawWord { return MonoidToGroupRawWord(syllableForm(w), n) }}
+	rel := G.sortedRelators() //the rules, and so the certificates, don't depend on map order
+	queue := make([]kbRule, 0, 2*n+len(rel))
+	for i := range n {
+		queue = append(queue, kbRule{lhs: RawWord{{i, 1}, {n + i, 1}}, rhs: RawWord{}}, kbRule{lhs: RawWord{{n + i, 1}, {i, 1}}, rhs: RawWord{}})
+	}
+	for _, r := range rel {
+		queue = append(queue, kbRule{lhs: expandRawWord(GroupToMonoidRawWord(r.seq, n)), rhs: RawWord{}, proof: proof{{Relator: r, Exponent: 1, Conjugator: EmptyWord()}}})
+	}
+	confluent := kb.complete(b, queue)
+	return kb, confluent
+}
+
+// Certify looks for a certificate that u = v in G, with Dehn's algorithm and then Knuth-Bendix on the monoid presentation with at most maxRules rules
+// Dehn's algorithm always finds one for Dehn presentations and one-relator groups with torsion (see DehnReduce and TorsionElement)
+// Returns ErrNoCertificate otherwise, which doesn't mean that u and v are different
+func (G *GroupPresentation) Certify(u, v Word, maxRules int) (*Certificate, error) {
+	c, err := G.certify(rulesBudget(context.Background(), maxRules), u, v)
+	if errors.Is(err, ErrRuleLimit) {
+		return nil, ErrNoCertificate
+	}
+	return c, err
+}
+
+// CertifyContext is Certify with at most limits.MaxRules rules whose left hand sides have at most limits.MaxWordLength letters
+// It returns ErrNoCertificate when u and v are different, and ctx.Err() or a *LimitError when it stops before knowing
+func (G *GroupPresentation) CertifyContext(ctx context.Context, u, v Word, limits Limits) (*Certificate, error) {
+	return G.certify(newBudget(ctx, limits), u, v)
+}
+
+// Certify with the limits of b, running Knuth-Bendix with budgets of rules doubling up to b's
+func (G *GroupPresentation) certify(b *budget, u, v Word) (*Certificate, error) {
+	if err := G.IsValidWord(u); err != nil {
+		return nil, err
+	} else if err := G.IsValidWord(v); err != nil {
+		return nil, err
+	}
+	w := ReduceRawWord(ConcatRawWord(u.seq, InvRawWord(v.seq)))
+	identity := func(w RawWord) RawWord { return w }
+	if rest, p := newProvingRewriter(G.provenDehnRules(), identity).rewrite(w); len(rest) == 0 {
+		return &Certificate{U: u, V: v, Factors: p}, nil
+	}
+	mw := expandRawWord(GroupToMonoidRawWord(w, G.gen))
+	maxRules := b.limits.MaxRules
+	for rules := 32; ; rules *= 2 {
+		if maxRules >= 0 {
+			rules = min(rules, maxRules)
+		}
+		bRules := &budget{ctx: b.ctx, limits: b.limits}
+		bRules.limits.MaxRules = rules
+		kb, confluent := G.provenKnuthBendix(bRules)
+		if rest, p := kb.reduce(mw); len(rest) == 0 {
+			return &Certificate{U: u, V: v, Factors: p}, nil
+		} else if confluent {
+			return nil, ErrNoCertificate //u and v are different
+		} else if !errors.Is(bRules.err, ErrRuleLimit) || rules == maxRules {
+			return nil, bRules.err
+		}
+	}
+}
+
+// CheckCertificate verifies c with free reduction only: its factors must be conjugates of relators of G or their inverses,
+// and c.U c.V^-1 must be freely equal to their product
+// Returns ErrInvalidCertificate if it is not a certificate that c.U = c.V in G
+func (G *GroupPresentation) CheckCertificate(c *Certificate) error {
+	if G.IsValidWord(c.U) != nil || G.IsValidWord(c.V) != nil {
+		return ErrInvalidCertificate
+	}
+	product := RawWord{}
+	for _, f := range c.Factors {
+		if abs(f.Exponent) != 1 || !G.relators().Has(canonicalRelator(f.Relator.seq)) || G.IsValidWord(f.Conjugator) != nil {
+			return ErrInvalidCertificate
+		}
+		product = ConcatRawWord(product, f.rawWord())
+	}
+	w := ConcatRawWord(ConcatRawWord(c.U.seq, InvRawWord(c.V.seq)), InvRawWord(product))
+	if len(ReduceRawWord(w)) != 0 {
+		return ErrInvalidCertificate
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestCertify(t *testing.T) {
+	commutator := RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}}
+	s3 := []RawWord{{{0, 2}}, {{1, 2}}, {{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}}
+	tests := []struct {
+		name string
+		gen  int
+		rel  []RawWord
+		u, v RawWord
+	}{
+		{"freely equal", 2, []RawWord{commutator}, RawWord{{0, 1}, {1, 1}, {1, -1}}, RawWord{{0, 1}}},
+		{"Z^2", 2, []RawWord{commutator}, RawWord{{0, 2}, {1, 1}}, RawWord{{1, 1}, {0, 2}}},
+		{"S3", 2, s3, RawWord{{0, 1}, {1, 1}, {0, 1}}, RawWord{{1, 1}, {0, 1}, {1, 1}}},
+		{"(ab)^3", 2, []RawWord{{{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}}, RawWord{{0, 1}, {1, 1}, {0, 1}}, RawWord{{1, -1}, {0, -1}, {1, -1}}},
+		{"BS(1, 2)", 2, []RawWord{{{1, 1}, {0, 1}, {1, -1}, {0, -2}}}, RawWord{{1, 1}, {0, 3}, {1, -1}}, RawWord{{0, 6}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet(wordsOf(tt.rel)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := G.Certify(p.NewWord(tt.u), p.NewWord(tt.v), 200)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := G.CheckCertificate(c); err != nil {
+				t.Errorf("certificate %v does not check", c)
+			}
+		})
+	}
+
+	// Dehn's algorithm certifies products of conjugates of relators in a surface group
+	surface := RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}, {2, 1}, {3, 1}, {2, -1}, {3, -1}}
+	G, _ := p.NewGroupPresentation(4, p.NewWordSet([]p.Word{p.NewWord(surface)}))
+	rng := rand.New(rand.NewPCG(9, 10))
+	for range 20 {
+		u := randomRawWord(rng, 4, 6)
+		v := p.ConcatRawWord(productOfConjugates(rng, surface, 4), u)
+		c, err := G.Certify(p.NewWord(u), p.NewWord(v), 0)
+		if err != nil || G.CheckCertificate(c) != nil {
+			t.Errorf("no valid certificate that %v = %v: %v", u, v, err)
+		}
+	}
+
+	// certificates don't depend on the random order of the relators in their map
+	u, v := p.NewWord(RawWord{{0, 1}, {1, 1}, {0, 1}}), p.NewWord(RawWord{{1, 1}, {0, 1}, {1, 1}})
+	var want []p.ConjugatedRelator
+	for i := range 20 {
+		H, err := p.NewGroupPresentation(2, p.NewWordSet(wordsOf(s3)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := H.Certify(u, v, 200)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			want = c.Factors
+		} else if !slices.EqualFunc(c.Factors, want, equalFactors) {
+			t.Fatalf("got certificates %v, then %v", want, c.Factors)
+		}
+	}
+}
+
+func equalFactors(x, y p.ConjugatedRelator) bool {
+	return p.EqualWord(x.Relator, y.Relator) && x.Exponent == y.Exponent && p.EqualWord(x.Conjugator, y.Conjugator)
+}
+
+func TestCertifyFails(t *testing.T) {
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}})}))
+	if _, err := G.Certify(p.NewWord(RawWord{{0, 1}}), p.NewWord(RawWord{{1, 1}}), 200); err != p.ErrNoCertificate {
+		t.Errorf("got error %v, want ErrNoCertificate", err)
+	}
+	c, err := G.Certify(p.NewWord(RawWord{{0, 1}, {1, 1}}), p.NewWord(RawWord{{1, 1}, {0, 1}}), 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := []p.Certificate{
+		{U: c.U, V: p.NewWord(RawWord{{0, 1}}), Factors: c.Factors},
+		{U: c.U, V: c.V, Factors: nil},
+		{U: c.U, V: c.V, Factors: append([]p.ConjugatedRelator{{Relator: p.NewWord(RawWord{{0, 2}}), Exponent: 1}}, c.Factors...)},
+	}
+	for _, d := range tampered {
+		if err := G.CheckCertificate(&d); err != p.ErrInvalidCertificate {
+			t.Errorf("got error %v for %v, want ErrInvalidCertificate", err, d)
+		}
+	}
+}
@@ -14,11 +14,21 @@ const (
 	Cyclic Class = "cyclic"
 	Finite Class = "finite"
 	Dehn Class = "dehn"
+	Automatic Class = "automatic" //shortlex automatic, see automatic.go
+	OneRelatorTorsion Class = "one_relator_torsion" //one relator which is a proper power, see torsion.go
+	Hyperbolic Class = "hyperbolic"
 )
 
 //helper to copy class maps defined here without mutating them. 
 //fallback to reset group classes upon error to be added
 func (G *GroupPresentation) addClasses(newClasses map[Class]bool) error {
+	G.mu.Lock()
+	defer G.mu.Unlock()
+	return G.addClassesLocked(newClasses)
+}
+
+//addClasses when G.mu is held
+func (G *GroupPresentation) addClassesLocked(newClasses map[Class]bool) error {
 	for c := range newClasses { 
 		if _, ok := G.classes[c]; !ok {
 			G.classes[c] = newClasses[c] 
@@ -31,6 +41,8 @@ func (G *GroupPresentation) addClasses(newClasses map[Class]bool) error {
 
 //for manually adding a property, positive or negative, but warning if that changed another value
 func (G * GroupPresentation) AddClass(c Class, t bool) error {	
+		G.mu.Lock()
+		defer G.mu.Unlock()
 		oldVal, ok := G.classes[c]
 		G.classes[c] = t 
 		if ok && oldVal != t {
@@ -42,6 +54,8 @@ func (G * GroupPresentation) AddClass(c Class, t bool) error {
 
 //this is for group properties that depend on a particular presentation rather than being 
 func (G *GroupPresentation) RemoveClass(c Class) {
+	G.mu.Lock()
+	defer G.mu.Unlock()
 	delete(G.classes, c)
 }
 
@@ -118,4 +132,15 @@ var cyclicGroupClasses = map[Class]bool{
 //abelian groups
 var abelianGroupClasses = map[Class]bool{
 	Abelian: true,
-}
\ No newline at end of file
+}
+
+//groups with a computed shortlex automatic structure
+var automaticGroupClasses = map[Class]bool{
+	Automatic: true,
+}
+
+//one relator groups whose relator is a proper power
+var oneRelatorTorsionGroupClasses = map[Class]bool{
+	OneRelatorTorsion: true,
+	Hyperbolic: true,
+}
@@ -0,0 +1,318 @@
+package presentation
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/lp"
+)
+
+// This file deals with commutator length (cl) and stable commutator length (scl) in free groups
+// Throughout, words are elements of the free group on their generators; relators of a presentation are ignored
+// We use the x^-1y^-1xy convention for commutators, just like groups.Comm
+
+var (
+	ErrNotInCommutatorSubgroup = errors.New("presentation: word is not in the commutator subgroup")
+	ErrCommutatorDecomposition = errors.New("presentation: could not decompose word into commutators")
+)
+
+// CommutatorRawWord returns the commutator x^-1y^-1xy, freely reduced
+func CommutatorRawWord(x, y RawWord) RawWord {
+	return ReduceRawWord(ConcatRawWord(ConcatRawWord(InvRawWord(x), InvRawWord(y)), ConcatRawWord(x, y)))
+}
+
+func CommutatorWord(x, y Word) Word {
+	return NewWord(CommutatorRawWord(x.seq, y.seq))
+}
+
+// checks that every generator has exponent sum 0, i.e. w is in the commutator subgroup of the free group
+func inCommutatorSubgroup(w RawWord) bool {
+	sums := make(map[int]int)
+	for _, u := range w {
+		sums[u[0]] += u[1]
+	}
+	for _, s := range sums {
+		if s != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CommutatorLengthRawWord computes the commutator length of w in the free group, i.e. the least g such that w is a product of g commutators
+// The second output is such a product: w freely reduces to the product of CommutatorRawWord(c[0], c[1]) over the returned pairs, in order
+// We use Culler's theorem: for a cyclically reduced w, cl(w) is the least genus of a surface obtained by gluing the sides of a polygon labeled by w in pairs x ~ x^-1
+// The search over pairings is exhaustive (with pruning) so this is exponential in the length of w; fine for words of length ~20, slow beyond that
+func CommutatorLengthRawWord(w RawWord) (int, [][2]RawWord, error) {
+	if !inCommutatorSubgroup(w) {
+		return 0, nil, ErrNotInCommutatorSubgroup
+	}
+	g := commutatorGenus(w)
+	comms, err := decomposeCommutators(w, g)
+	if err != nil {
+		return 0, nil, err
+	}
+	return g, comms, nil
+}
+
+func CommutatorLengthWord(w Word) (int, [][2]Word, error) {
+	g, raw, err := CommutatorLengthRawWord(w.seq)
+	if err != nil {
+		return 0, nil, err
+	}
+	comms := make([][2]Word, len(raw))
+	for i, c := range raw {
+		comms[i] = [2]Word{NewWord(c[0]), NewWord(c[1])}
+	}
+	return g, comms, nil
+}
+
+// minimal genus of a polygon gluing for w, which must be in the commutator subgroup
+// the Euler characteristic of the glued surface is V - n/2 + 1 where V is the number of vertex classes, so we maximize V
+func commutatorGenus(w RawWord) int {
+	r, _ := CyclicReduceRawWord(w)
+	letters := expandRawWord(r)
+	n := len(letters)
+	if n == 0 {
+		return 0
+	}
+	// the polygon has vertex i at the start of side i, gluing side i (x) with side j (x^-1) identifies i ~ j+1 and i+1 ~ j
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			i = parent[i]
+		}
+		return i
+	}
+	paired := make([]bool, n)
+	bestV := 0
+	var search func(classes int)
+	search = func(classes int) {
+		if classes <= bestV {
+			return //gluing never increases the number of vertex classes
+		}
+		i := 0
+		for i < n && (paired[i] || letters[i][1] < 0) {
+			i++
+		}
+		if i == n {
+			bestV = classes //everything is paired since exponent sums are 0
+			return
+		}
+		paired[i] = true
+		for j := range n {
+			if paired[j] || letters[j][0] != letters[i][0] || letters[j][1] > 0 {
+				continue
+			}
+			paired[j] = true
+			// union by hand so we can undo it (no path compression)
+			var undo []int
+			merged := classes
+			for _, e := range [][2]int{{i, (j + 1) % n}, {(i + 1) % n, j}} {
+				a, b := find(e[0]), find(e[1])
+				if a != b {
+					parent[a] = b
+					undo = append(undo, a)
+					merged--
+				}
+			}
+			search(merged)
+			for _, a := range undo {
+				parent[a] = a
+			}
+			paired[j] = false
+		}
+		paired[i] = false
+	}
+	search(n)
+	return (1 + n/2 - bestV) / 2
+}
+
+// splits w (in the commutator subgroup, of commutator length g) into g commutators
+// we look for interleaved letters w ~ x B y C x^-1 D y^-1 E (up to rotation) and use the identity
+// x B y C x^-1 D y^-1 E = [(xB)^-1, (yCB)^-1] . y (CBD) y^-1 E
+// keeping the choice whenever the remainder has commutator length g-1
+func decomposeCommutators(w RawWord, g int) ([][2]RawWord, error) {
+	if g == 0 {
+		if len(ReduceRawWord(w)) != 0 {
+			return nil, ErrCommutatorDecomposition
+		}
+		return [][2]RawWord{}, nil
+	}
+	r, c := CyclicReduceRawWord(w) //w = c^-1 r c
+	letters := expandRawWord(r)
+	n := len(letters)
+	for i := range n {
+		for k := range n {
+			if letters[k][0] != letters[i][0] || letters[k][1] != -letters[i][1] {
+				continue
+			}
+			for j := range n {
+				for l := range n {
+					// rotate so that i is at the start, the letters then need to appear in the order i < j < k < l
+					rj, rk, rl := (j-i+n)%n, (k-i+n)%n, (l-i+n)%n
+					if !(0 < rj && rj < rk && rk < rl) || letters[l][0] != letters[j][0] || letters[l][1] != -letters[j][1] {
+						continue
+					}
+					rotated := append(append(RawWord{}, letters[i:]...), letters[:i]...)
+					x, y := rotated[0:1], rotated[rj:rj+1]
+					B, C, D, E := rotated[1:rj], rotated[rj+1:rk], rotated[rk+1:rl], rotated[rl+1:]
+					a := ConcatRawWord(x, B)
+					b := ConcatRawWord(y, ConcatRawWord(C, B))
+					M := ConcatRawWord(C, ConcatRawWord(B, D))
+					rest := ReduceRawWord(ConcatRawWord(ConcatRawWord(y, M), ConcatRawWord(InvRawWord(y), E)))
+					// undo the rotation and the cyclic reduction
+					conj := ReduceRawWord(ConcatRawWord(InvRawWord(letters[:i]), c)) //w = conj^-1 rotated conj
+					rest = ConjugateRawWord(rest, conj)
+					if commutatorGenus(rest) != g-1 {
+						continue
+					}
+					tail, err := decomposeCommutators(rest, g-1)
+					if err != nil {
+						return nil, err
+					}
+					first := [2]RawWord{ConjugateRawWord(InvRawWord(a), conj), ConjugateRawWord(InvRawWord(b), conj)}
+					return append([][2]RawWord{first}, tail...), nil
+				}
+			}
+		}
+	}
+	return nil, ErrCommutatorDecomposition
+}
+
+// SCLRawWord computes the stable commutator length of w in the free group via Calegari's scallop linear program
+// An admissible surface for w is built out of rectangles (pairs of letters x, x^-1 of w glued together) and polygons (disks whose sides are short ends of rectangles)
+// If the boundary of the surface covers w a total of N times, then -χ = nN/2 - χ(polygons) where n = |w| (cyclically reduced), and scl(w) = inf -χ/2N
+// Polygons can have arbitrarily many sides, so just like scallop we cut them into triangles and bigons along diagonals, each diagonal costing 1 to χ
+// This keeps the LP polynomial in n (O(n^3) columns), but it is still dense exact simplex so expect it to be slow beyond length ~16
+func SCLRawWord(w RawWord) (*big.Rat, error) {
+	if !inCommutatorSubgroup(w) {
+		return nil, ErrNotInCommutatorSubgroup
+	}
+	r, _ := CyclicReduceRawWord(w)
+	letters := expandRawWord(r)
+	n := len(letters)
+	if n == 0 {
+		return new(big.Rat), nil
+	}
+
+	// polygon corners are labeled by the letter that starts there
+	// a rectangle side goes from corner b to corner a+1 when letters a and b are inverses: the polygon crosses the rectangle {a,b} from b to a
+	// a diagonal can go from any corner to any other corner
+	type arrow struct {
+		from, to int
+		real     bool
+	}
+	isReal := func(u, v int) bool {
+		a := (v - 1 + n) % n
+		return letters[a][0] == letters[u][0] && letters[a][1] == -letters[u][1]
+	}
+	// the pieces, as lists of arrows. We only need triangles and bigons with at least one rectangle side (fan triangulations of polygons)
+	var pieces [][]arrow
+	var addPiece func(corners []int, piece []arrow, hasReal bool)
+	addPiece = func(corners []int, piece []arrow, hasReal bool) {
+		k := len(piece)
+		if k == len(corners) {
+			if hasReal {
+				pieces = append(pieces, append([]arrow{}, piece...))
+			}
+			return
+		}
+		u, v := corners[k], corners[(k+1)%len(corners)]
+		if isReal(u, v) {
+			addPiece(corners, append(piece, arrow{u, v, true}), true)
+		}
+		addPiece(corners, append(piece, arrow{u, v, false}), hasReal)
+	}
+	for u := range n {
+		for v := u + 1; v < n; v++ {
+			addPiece([]int{u, v}, nil, false)
+			for t := u + 1; t < n; t++ {
+				if t != v { //u is the smallest corner so both orientations u,v,t and u,t,v get visited
+					addPiece([]int{u, v, t}, nil, false)
+				}
+			}
+		}
+	}
+
+	// constraints: for every rectangle {a,b}, the polygons cross it as often from a as from b
+	// every diagonal is used as often in each direction
+	// and for every letter i, the rectangles at i add up to N = 1 (scl is homogeneous so we may normalize)
+	one := big.NewRat(1, 1)
+	half := big.NewRat(1, 2)
+	var A [][]*big.Rat
+	var rhs []*big.Rat
+	addRow := func(coeff func(arrow) int, target int64) {
+		row := make([]*big.Rat, len(pieces))
+		for k, p := range pieces {
+			row[k] = new(big.Rat)
+			for _, e := range p {
+				row[k].Add(row[k], big.NewRat(int64(coeff(e)), 1))
+			}
+		}
+		A = append(A, row)
+		rhs = append(rhs, big.NewRat(target, 1))
+	}
+	for b := range n {
+		for a := b + 1; a < n; a++ {
+			if !isReal(b, (a+1)%n) {
+				continue
+			}
+			addRow(func(e arrow) int {
+				switch {
+				case e.real && e.from == b && e.to == (a+1)%n:
+					return 1
+				case e.real && e.from == a && e.to == (b+1)%n:
+					return -1
+				}
+				return 0
+			}, 0)
+		}
+	}
+	for u := range n {
+		for v := u + 1; v < n; v++ {
+			addRow(func(e arrow) int {
+				switch {
+				case !e.real && e.from == u && e.to == v:
+					return 1
+				case !e.real && e.from == v && e.to == u:
+					return -1
+				}
+				return 0
+			}, 0)
+		}
+	}
+	for i := range n {
+		addRow(func(e arrow) int {
+			if e.real && e.to == (i+1)%n {
+				return 1
+			}
+			return 0
+		}, 1)
+	}
+	// objective: χ of the polygons, i.e. one per piece minus one per glued diagonal (each diagonal has two arrows)
+	obj := make([]*big.Rat, len(pieces))
+	for k, p := range pieces {
+		obj[k] = new(big.Rat).Set(one)
+		for _, e := range p {
+			if !e.real {
+				obj[k].Sub(obj[k], half)
+			}
+		}
+	}
+	_, chi, err := lp.Maximize(obj, A, rhs)
+	if err != nil {
+		return nil, err
+	}
+	// scl = (n/2 - χ(polygons))/2 with N = 1
+	scl := big.NewRat(int64(n), 4)
+	return scl.Sub(scl, chi.Mul(chi, half)), nil
+}
+
+func SCLWord(w Word) (*big.Rat, error) {
+	return SCLRawWord(w.seq)
+}
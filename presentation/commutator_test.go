@@ -0,0 +1,109 @@
+package presentation_test
+
+import (
+	"math/big"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestCommutatorLength(t *testing.T) {
+	ab := RawWord{{0, -1}, {1, -1}, {0, 1}, {1, 1}}
+	cd := RawWord{{2, -1}, {3, -1}, {2, 1}, {3, 1}}
+	tests := []struct {
+		name    string
+		w       RawWord
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			w:    RawWord{},
+			want: 0,
+		},
+		{
+			name: "single commutator",
+			w:    ab,
+			want: 1,
+		},
+		{
+			name: "conjugated commutator",
+			w:    p.ConjugateRawWord(ab, RawWord{{2, 3}, {0, -1}}),
+			want: 1,
+		},
+		{
+			name: "two commutators",
+			w:    p.ConcatRawWord(ab, cd),
+			want: 2,
+		},
+		{
+			name: "cube of a commutator (Culler)",
+			w:    p.PowRawWord(3, ab),
+			want: 2,
+		},
+		{
+			name:    "not in the commutator subgroup",
+			w:       RawWord{{0, 2}, {1, 1}, {0, -1}, {1, -1}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, comms, err := p.CommutatorLengthRawWord(tt.w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wanted error %v got error %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want || len(comms) != tt.want {
+				t.Fatalf("CommutatorLengthRawWord(%v) = %v with %v commutators, want %v", tt.w, got, len(comms), tt.want)
+			}
+			prod := RawWord{}
+			for _, c := range comms {
+				prod = p.ReduceRawWord(p.ConcatRawWord(prod, p.CommutatorRawWord(c[0], c[1])))
+			}
+			if !p.EqualRawWord(prod, p.ReduceRawWord(tt.w)) {
+				t.Fatalf("product of commutators %v is %v, want %v", comms, prod, p.ReduceRawWord(tt.w))
+			}
+		})
+	}
+}
+
+func TestSCL(t *testing.T) {
+	ab := RawWord{{0, -1}, {1, -1}, {0, 1}, {1, 1}}
+	tests := []struct {
+		name string
+		w    RawWord
+		want *big.Rat
+	}{
+		{
+			name: "commutator",
+			w:    ab,
+			want: big.NewRat(1, 2),
+		},
+		{
+			name: "square of a commutator",
+			w:    p.PowRawWord(2, ab),
+			want: big.NewRat(1, 1),
+		},
+		{
+			name: "product of commutators in disjoint generators",
+			w:    p.ConcatRawWord(ab, RawWord{{2, -1}, {3, -1}, {2, 1}, {3, 1}}),
+			want: big.NewRat(3, 2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.SCLRawWord(tt.w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Cmp(tt.want) != 0 {
+				t.Fatalf("SCLRawWord(%v) = %v want %v", tt.w, got, tt.want)
+			}
+		})
+	}
+}
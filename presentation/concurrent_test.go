@@ -0,0 +1,83 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+// a presentation shared by a pool of workers gives the same answers as a fresh one used by a single goroutine
+func TestConcurrentUse(t *testing.T) {
+	presentations := []struct {
+		name string
+		gen  int
+		rel  []RawWord
+	}{
+		{"BS(1, 2)", 2, []RawWord{{{1, 1}, {0, 1}, {1, -1}, {0, -2}}}},
+		{"torsion", 2, []RawWord{{{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}}},
+		{"C'(1/6)", 2, []RawWord{{{0, 1}, {1, 1}, {0, 2}, {1, 2}, {0, 3}, {1, 3}, {0, 4}, {1, 4}}}},
+		{"S3", 2, []RawWord{{{0, 2}}, {{1, 2}}, {{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}}},
+	}
+	for _, tt := range presentations {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(15, 16))
+			words := make([]p.Word, 20)
+			for i := range words {
+				words[i] = p.NewWord(p.ConcatRawWord(productOfConjugates(rng, tt.rel[0], tt.gen), randomRawWord(rng, tt.gen, 2)))
+			}
+			fresh, _ := p.NewGroupPresentation(tt.gen, p.NewWordSet(wordsOf(tt.rel)))
+			want := make([]p.Verdict, len(words))
+			for i, w := range words {
+				want[i], _ = fresh.Decide(w, p.EmptyWord())
+			}
+			G, _ := p.NewGroupPresentation(tt.gen, p.NewWordSet(wordsOf(tt.rel)))
+			var wg sync.WaitGroup
+			for worker := range 4 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i, w := range words {
+						if got, _ := G.Decide(w, p.EmptyWord()); got != want[i] {
+							t.Errorf("worker %d: Decide(%v) = %v, want %v", worker, w, got, want[i])
+						}
+						G.DehnReduce(w)
+						G.FindSeparatingQuotient(w)
+						G.IsTrivialOneRelator(w)
+						G.Classes()
+					}
+					G.AddClass(p.Finite, tt.name == "S3")
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestSimplifyCyclicPresentationConcurrently(t *testing.T) {
+	G, _ := p.NewGroupPresentation(1, p.NewWordSet(wordsOf([]RawWord{{{0, 4}}, {{0, 6}}})))
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if r, err := G.Reduce(p.NewWord(RawWord{{0, 5}})); err != nil || r.Len() != 1 {
+				t.Errorf("got %v, %v, want a", r, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := G.SimplifyCyclicPresentation(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if rel := G.Relations(); len(rel) != 1 || !rel.Has(p.NewWord(RawWord{{0, 2}})) {
+		t.Errorf("got relations %v, want a^2", rel)
+	}
+	if !G.Classes()[p.OneRelator] {
+		t.Errorf("got classes %v, want one relator", G.Classes())
+	}
+}
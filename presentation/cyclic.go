@@ -31,17 +31,26 @@ func MultiGCD(nums []int) int {
 	return gcd
 }
 
+// SimplifyCyclicPresentation replaces the relators of a cyclic presentation <a | a^n_1, ..., a^n_k> by the single relator a^gcd(n_1, ..., n_k)
+// This is a Tietze move, so derived data about the relators is dropped and recomputed on demand
 func (G *GroupPresentation) SimplifyCyclicPresentation() error {
+	G.lazy.Lock()
+	defer G.lazy.Unlock()
+	G.mu.Lock()
+	defer G.mu.Unlock()
 	if val, ok := G.classes[Cyclic]; !ok || !val {
 		return errors.New("This Group is not cyclic")
+	} else if len(G.rel) <= 1 {
+		return nil //already simplified
 	}
 	exps := make([]int, 0, len(G.rel)) //we'll extract the exponent of each relation
-	//each relation is already in the form Word{{0,n}} due to the word reduction in NewGroupPresentation
+	//each relation is already in the form Word{{0,n}} with n > 0 due to the canonical form of relators in NewGroupPresentation
 	for _, r := range G.rel {
 		exps = append(exps, r.seq[0][1])
 	}
-	combinedRel := NewWord(RawWord{{0, MultiGCD(exps)}})
-	G.rel.Add(combinedRel)
-	G.addClasses(oneRelatorGroupClasses)
-	return nil
+	rel := make(WordSet) //G.rel is replaced rather than modified, see GroupPresentation
+	rel.Add(canonicalRelator(RawWord{{0, MultiGCD(exps)}}))
+	G.rel = rel
+	G.magnus, G.dehn = nil, nil
+	return G.addClassesLocked(oneRelatorGroupClasses)
 }
@@ -0,0 +1,114 @@
+package presentation
+
+// A cyclic word is a word up to rotation, i.e. a conjugacy class of the free group
+// Relators are naturally cyclic words: r, its cyclic permutations (and its inverse) all define the same normal closure
+// Optionally, a cyclic word can also be taken up to inversion, which is what we use for relators
+
+// This struct is treated as immutable
+// rep is the canonical representative, so two CyclicWords are equal exactly when their representatives are
+type CyclicWord struct {
+	rep           Word
+	upToInversion bool
+}
+
+// Constructor for a CyclicWord based on a RawWord
+// The representative is the least rotation of the cyclic reduction of w (and of its inverse if upToInversion), see CanonicalRotationRawWord
+func NewCyclicWord(w [][2]int, upToInversion bool) CyclicWord {
+	rep := CanonicalRotationRawWord(w)
+	if upToInversion {
+		if inv := CanonicalRotationRawWord(InvRawWord(w)); lessSyllables(inv, rep) {
+			rep = inv
+		}
+	}
+	return CyclicWord{rep: NewWord(rep), upToInversion: upToInversion}
+}
+
+func CyclicWordFromWord(w Word, upToInversion bool) CyclicWord {
+	return NewCyclicWord(w.seq, upToInversion)
+}
+
+// returns the canonical representative of c, which is cyclically reduced
+func (c CyclicWord) Word() Word { return c.rep }
+
+// whether c was taken up to inversion
+func (c CyclicWord) UpToInversion() bool { return c.upToInversion }
+
+// Gives the true length of the cyclic word, which is the length of its cyclic reduction
+func (c CyclicWord) Len() int { return c.rep.Len() }
+
+// checks if two CyclicWords are equal, i.e. conjugate in the free group (or conjugate to each other's inverse if both are up to inversion)
+func EqualCyclicWord(u, v CyclicWord) bool {
+	return u.upToInversion == v.upToInversion && EqualWord(u.rep, v.rep)
+}
+
+// order on syllables used for canonical representatives: by generator, then positive exponents first, then by absolute value
+// positive exponents come first so that e.g. a^3 rather than a^-3 represents {a^3, a^-3}
+func lessSyllable(x, y [2]int) bool {
+	switch {
+	case x[0] != y[0]:
+		return x[0] < y[0]
+	case sign(x[1]) != sign(y[1]):
+		return x[1] > 0
+	}
+	return abs(x[1]) < abs(y[1])
+}
+
+// lexicographic order on syllables, shorter words first
+func lessSyllables(a, b RawWord) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	for i := range a {
+		if lessSyllable(a[i], b[i]) {
+			return true
+		} else if lessSyllable(b[i], a[i]) {
+			return false
+		}
+	}
+	return false //equal
+}
+
+// Index of the lexicographically least rotation of s in O(n) time (the two-pointer minimal rotation algorithm)
+// i and j are the two candidate starting points and k the length of their common prefix
+// when the candidates differ at k, the bigger one and the next k starting points after it can't be least, so we jump past them
+func leastRotation[T any](s []T, less func(T, T) bool) int {
+	n := len(s)
+	i, j, k := 0, 1, 0
+	for i < n && j < n && k < n {
+		a, b := s[(i+k)%n], s[(j+k)%n]
+		switch {
+		case less(a, b):
+			j += k + 1
+			k = 0
+		case less(b, a):
+			i += k + 1
+			k = 0
+		default:
+			k++
+			continue
+		}
+		if i == j {
+			j++
+		}
+	}
+	return min(i, j)
+}
+
+// The canonical rotation of w: the least rotation (syllable by syllable) of the cyclic reduction of w
+// The first and last syllables of a cyclic reduction have different generators (unless there is only one), so its rotations at syllable boundaries are exactly its cyclic permutations that start a new syllable
+// Two words are conjugate in the free group exactly when their canonical rotations are equal
+func CanonicalRotationRawWord(w RawWord) RawWord {
+	r, _ := CyclicReduceRawWord(w)
+	i := leastRotation(r, lessSyllable)
+	return ConcatRawWord(r[i:], r[:i])
+}
+
+func CanonicalRotationWord(w Word) Word {
+	return NewWord(CanonicalRotationRawWord(w.seq))
+}
+
+// relators are stored as the representatives of their cyclic words up to inversion
+// so that a relator, its cyclic permutations and its inverse are a single WordSet entry
+func canonicalRelator(r RawWord) Word {
+	return NewCyclicWord(r, true).Word()
+}
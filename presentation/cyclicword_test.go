@@ -0,0 +1,112 @@
+package presentation_test
+
+import (
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestCanonicalRotation(t *testing.T) {
+	tests := []struct {
+		name string
+		in   RawWord
+		want RawWord
+	}{
+		{
+			name: "empty",
+			in:   RawWord{},
+			want: RawWord{},
+		},
+		{
+			name: "already canonical",
+			in:   RawWord{{0, 1}, {1, 1}},
+			want: RawWord{{0, 1}, {1, 1}},
+		},
+		{
+			name: "rotation",
+			in:   RawWord{{2, 1}, {0, 3}, {1, -1}},
+			want: RawWord{{0, 3}, {1, -1}, {2, 1}},
+		},
+		{
+			name: "cyclic reduction first",
+			in:   RawWord{{1, 2}, {0, 1}, {2, 5}, {1, -2}},
+			want: RawWord{{0, 1}, {2, 5}},
+		},
+		{
+			name: "first and last syllables merge",
+			in:   RawWord{{0, 1}, {1, 1}, {0, 2}},
+			want: RawWord{{0, 3}, {1, 1}},
+		},
+		{
+			name: "positive exponents first",
+			in:   RawWord{{0, -1}, {1, 1}, {0, 1}, {1, 1}},
+			want: RawWord{{0, 1}, {1, 1}, {0, -1}, {1, 1}},
+		},
+		{
+			name: "periodic",
+			in:   RawWord{{1, 1}, {0, 1}, {1, 1}, {0, 1}},
+			want: RawWord{{0, 1}, {1, 1}, {0, 1}, {1, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.CanonicalRotationRawWord(tt.in)
+			if !p.EqualRawWord(got, tt.want) {
+				t.Fatalf("CanonicalRotationRawWord(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqualCyclicWord(t *testing.T) {
+	tests := []struct {
+		name          string
+		first         RawWord
+		second        RawWord
+		upToInversion bool
+		want          bool
+	}{
+		{
+			name:   "cyclic permutation",
+			first:  RawWord{{0, 1}, {1, 2}, {2, -1}},
+			second: RawWord{{1, 1}, {2, -1}, {0, 1}, {1, 1}},
+			want:   true,
+		},
+		{
+			name:   "conjugate",
+			first:  RawWord{{0, 1}, {1, 1}},
+			second: RawWord{{2, 4}, {1, 1}, {0, 1}, {2, -4}},
+			want:   true,
+		},
+		{
+			name:   "inverse is a different cyclic word",
+			first:  RawWord{{0, 1}, {1, 1}},
+			second: RawWord{{1, -1}, {0, -1}},
+			want:   false,
+		},
+		{
+			name:          "inverse up to inversion",
+			first:         RawWord{{0, 1}, {1, 1}},
+			second:        RawWord{{0, -1}, {1, -1}},
+			upToInversion: true,
+			want:          true,
+		},
+		{
+			name:          "same letters, different cyclic words",
+			first:         RawWord{{0, 2}, {1, 1}, {0, 1}, {1, 1}},
+			second:        RawWord{{0, 1}, {1, 2}, {0, 2}},
+			upToInversion: true,
+			want:          false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, v := p.NewCyclicWord(tt.first, tt.upToInversion), p.NewCyclicWord(tt.second, tt.upToInversion)
+			if got := p.EqualCyclicWord(u, v); got != tt.want {
+				t.Fatalf("EqualCyclicWord(%v, %v) = %v, want %v (representatives %v and %v)", tt.first, tt.second, got, tt.want, u.Word(), v.Word())
+			}
+		})
+	}
+}
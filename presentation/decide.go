@@ -0,0 +1,55 @@
+package presentation
+
+// Three-valued answers for questions we can't always settle, like equality of words when no complete word problem solver applies
+
+type Verdict int
+
+const (
+	Unknown Verdict = iota
+	Yes
+	No
+)
+
+// the verdicts of Decide
+const (
+	Equal    = Yes
+	NotEqual = No
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Yes:
+		return "yes"
+	case No:
+		return "no"
+	}
+	return "unknown"
+}
+
+// Decide tells whether u and v are equal in G
+// Equal comes from reducing u v^-1 to the empty word, and NotEqual either from a complete solver (see Guarantee) or from a finite quotient separating u and v (see FindSeparatingQuotient)
+// Otherwise the answer is Unknown
+func (G *GroupPresentation) Decide(u, v Word) (Verdict, error) {
+	r, err := G.ReduceWithInfo(ConcatWord(u, InvWord(v)))
+	if err != nil {
+		return Unknown, err
+	}
+	if CompactLen(r.Word) == 0 {
+		return Equal, nil
+	} else if r.Guarantee >= Complete {
+		return NotEqual, nil
+	} else if _, ok := G.FindSeparatingQuotient(r.Word); ok {
+		return NotEqual, nil
+	}
+	return Unknown, nil
+}
+
+// InClass tells whether G is in the class c, as recorded in its classes
+func (G *GroupPresentation) InClass(c Class) Verdict {
+	if val, ok := G.class(c); !ok {
+		return Unknown
+	} else if val {
+		return Yes
+	}
+	return No
+}
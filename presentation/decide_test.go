@@ -0,0 +1,90 @@
+package presentation_test
+
+import (
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestDecide(t *testing.T) {
+	commutator := RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}}
+	tests := []struct {
+		name string
+		gen  int
+		rel  []RawWord
+		u, v RawWord
+		want p.Verdict
+	}{
+		{"Z^2, equal", 2, []RawWord{commutator}, RawWord{{0, 1}, {1, 1}}, RawWord{{1, 1}, {0, 1}}, p.Equal},
+		{"Z^2, not equal", 2, []RawWord{commutator}, RawWord{{0, 1}}, RawWord{{1, 1}}, p.NotEqual},
+		{"Z/2 * Z/3, freely equal", 2, []RawWord{{{0, 2}}, {{1, 3}}}, RawWord{{0, 1}, {1, 1}, {1, -1}}, RawWord{{0, 1}}, p.Equal},
+		{"Z/2 * Z/3, a^2 = 1 is not found by free reduction", 2, []RawWord{{{0, 2}}, {{1, 3}}}, RawWord{{0, 2}}, nil, p.Unknown},
+		{"Z/2 * Z/3, separated by Z/2", 2, []RawWord{{{0, 2}}, {{1, 3}}}, RawWord{{0, 1}}, nil, p.NotEqual},
+		{"Z/2 * Z/3, separated by S3", 2, []RawWord{{{0, 2}}, {{1, 3}}}, RawWord{{0, 1}, {1, 1}}, RawWord{{1, 1}, {0, 1}}, p.NotEqual},
+		// a b a^-1 = b^2 and b a b^-1 = a^2 present the trivial group, so no quotient separates anything
+		{"trivial group in disguise", 2, []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -2}}, {{1, 1}, {0, 1}, {1, -1}, {0, -2}}}, RawWord{{0, 1}}, nil, p.Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet(wordsOf(tt.rel)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := G.Decide(p.NewWord(tt.u), p.NewWord(tt.v))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Decide(%v, %v) = %v, want %v", tt.u, tt.v, got, tt.want)
+			}
+		})
+	}
+
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet(nil))
+	if _, err := G.Decide(p.NewWord(RawWord{{2, 1}}), p.EmptyWord()); err == nil {
+		t.Errorf("no error for an invalid word")
+	}
+}
+
+func TestFindSeparatingQuotient(t *testing.T) {
+	// in Z/2 * Z/3, a b has infinite order but a b a^-1 b^-1 dies in the abelianization
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet(wordsOf([]RawWord{{{0, 2}}, {{1, 3}}})))
+	w := p.NewWord(RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}})
+	images, ok := G.FindSeparatingQuotient(w)
+	if !ok {
+		t.Fatalf("no quotient separates %v", w)
+	}
+	// check that it is a homomorphism sending w to a nontrivial permutation
+	apply := func(w RawWord) []int {
+		d := len(images[0])
+		q := make([]int, d)
+		for i := range q {
+			q[i] = i
+			for _, x := range w {
+				for range (x[1]%(2*3*4*5) + 2*3*4*5) % (2 * 3 * 4 * 5) { //the order of a permutation of degree at most 5 divides 120
+					q[i] = images[x[0]][q[i]]
+				}
+			}
+		}
+		return q
+	}
+	isIdentity := func(q []int) bool {
+		for i := range q {
+			if q[i] != i {
+				return false
+			}
+		}
+		return true
+	}
+	if !isIdentity(apply(RawWord{{0, 2}})) || !isIdentity(apply(RawWord{{1, 3}})) || isIdentity(apply(RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}})) {
+		t.Errorf("images %v do not separate %v", images, w)
+	}
+}
+
+func TestInClass(t *testing.T) {
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet(nil))
+	H, _ := p.NewGroupPresentation(2, p.NewWordSet(wordsOf([]RawWord{{{0, 2}}, {{1, 3}}})))
+	if G.InClass(p.Free) != p.Yes || G.InClass(p.Abelian) != p.No || H.InClass(p.Free) != p.Unknown {
+		t.Errorf("got %v, %v, %v, want yes, no, unknown", G.InClass(p.Free), G.InClass(p.Abelian), H.InClass(p.Free))
+	}
+}
@@ -1,7 +1,39 @@
 package presentation
 
-//Dehn's algorithm 
+// Dehn's algorithm: while w contains more than half of a cyclic conjugate u v of a relator or its inverse, i.e. u with |u| > |v|, replace u by the shorter v^-1
+// A presentation is a Dehn presentation when this reduces every trivial word to the empty word, which holds e.g. under the C'(1/6) small cancellation condition
+// Other words are not given unique normal forms
+// WARNING: for presentations which are not Dehn presentations, a nonempty result does not mean that w is nontrivial
 func (G *GroupPresentation) DehnReduce(w Word) Word {
-	//To be finished upon building word rewriting systems
-	return EmptyWord()
-}
\ No newline at end of file
+	return NewWord(G.dehnRewriter().Rewrite(w.seq))
+}
+
+// the rules of Dehn's algorithm, compiled once and kept in G
+func (G *GroupPresentation) dehnRewriter() *Rewriter {
+	G.lazy.Lock()
+	defer G.lazy.Unlock()
+	if G.dehn == nil {
+		R := RewritingSystem{}
+		for _, r := range G.relators() {
+			S := cyclicConjugateRules(r.seq, r.Len()/2+1)
+			R.LHS, R.RHS = append(R.LHS, S.LHS...), append(R.RHS, S.RHS...)
+		}
+		G.dehn, _ = R.Compile(LeftmostFirst) //same number of sides
+	}
+	return G.dehn
+}
+
+// the rules u -> v^-1 for the cyclic conjugates u v of r and r^-1 where u has k letters, r being cyclically reduced first
+// they shorten words when k is more than half the length of r
+func cyclicConjugateRules(r RawWord, k int) RewritingSystem {
+	reduced, _ := CyclicReduceRawWord(r)
+	R := RewritingSystem{}
+	for _, s := range []RawWord{expandRawWord(reduced), expandRawWord(InvRawWord(reduced))} {
+		for i := range s {
+			conj := append(append(RawWord{}, s[i:]...), s[:i]...)
+			R.LHS = append(R.LHS, ReduceRawWord(conj[:k]))
+			R.RHS = append(R.RHS, InvRawWord(ReduceRawWord(conj[k:])))
+		}
+	}
+	return R
+}
@@ -6,7 +6,7 @@ func NewFreeGroup(rank int) (*GroupPresentation, error) {
 	}
 	G := &GroupPresentation{
 		gen:     rank,
-		rel:     make(map[string]Word),
+		rel:     make(WordSet),
 		classes: make(map[Class]bool),
 	}
 	switch rank {
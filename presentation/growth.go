@@ -0,0 +1,203 @@
+package presentation
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/fsa"
+)
+
+// Balls and spheres in the Cayley graph of a presentation, with respect to its generators
+// The sphere of radius n is the set of elements of word length exactly n, and the ball the set of elements of word length at most n
+
+var (
+	ErrNegativeRadius      = errors.New("presentation: negative radius")
+	ErrWordProblemUnsolved = errors.New("presentation: Reduce doesn't solve the word problem for this presentation")
+	ErrNoRegularNormalForm = errors.New("presentation: no regular language of geodesic normal forms known for this presentation")
+)
+
+// the spheres of radius 0, ..., n, found by breadth first search in the Cayley graph
+// Elements are represented by their reductions under Reduce, see ReduceWithInfo
+// When the solver gives normal forms, equal elements have the same reduction. When it is only Complete, e.g. a and a^-4 in <a | a^5>,
+// we also compare each new element u with those of the last two spheres and the new one, which are the only ones at distance at most 1 from it, using that u = v when u v^-1 reduces to the empty word
+func (G *GroupPresentation) spheres(n int) ([][]Word, error) {
+	if n < 0 {
+		return nil, ErrNegativeRadius
+	}
+	id, err := G.ReduceWithInfo(EmptyWord())
+	if err != nil {
+		return nil, err
+	}
+	if id.Guarantee < Complete {
+		return nil, ErrWordProblemUnsolved
+	}
+	seen := NewWordSet([]Word{id.Word})
+	spheres := [][]Word{{id.Word}}
+	for i := 1; i <= n; i++ {
+		sphere := make([]Word, 0)
+		for _, w := range spheres[i-1] {
+			for g := range G.gen {
+				for _, e := range []int{1, -1} {
+					v, err := G.Reduce(ConcatWord(w, NewWord(RawWord{{g, e}})))
+					if err != nil {
+						return nil, err
+					}
+					if seen.Has(v) {
+						continue
+					}
+					if id.Guarantee < NormalForms {
+						found, err := G.equalToAny(v, spheres[max(i-2, 0)], spheres[i-1], sphere)
+						if err != nil {
+							return nil, err
+						}
+						if found {
+							continue
+						}
+					}
+					seen.Add(v)
+					sphere = append(sphere, v)
+				}
+			}
+		}
+		spheres = append(spheres, sphere)
+	}
+	return spheres, nil
+}
+
+// whether u is equal in G to a word of one of the lists, for a Complete solver
+func (G *GroupPresentation) equalToAny(u Word, lists ...[]Word) (bool, error) {
+	for _, l := range lists {
+		for _, v := range l {
+			r, err := G.Reduce(ConcatWord(u, InvWord(v)))
+			if err != nil {
+				return false, err
+			}
+			if len(r.seq) == 0 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Ball returns the elements of word length at most n, as reductions given by Reduce
+// They come by increasing word length, then in the order they were found multiplying shorter elements by x_0, x_0^-1, x_1, ...
+// Elements are told apart with Reduce, so this returns ErrWordProblemUnsolved when its solver for G is not Complete (see ReduceWithInfo)
+func (G *GroupPresentation) Ball(n int) ([]Word, error) {
+	spheres, err := G.spheres(n)
+	if err != nil {
+		return nil, err
+	}
+	ball := make([]Word, 0)
+	for _, sphere := range spheres {
+		ball = append(ball, sphere...)
+	}
+	return ball, nil
+}
+
+// SphereSizes returns the numbers of elements of word length 0, 1, ..., n, with the same errors as Ball
+func (G *GroupPresentation) SphereSizes(n int) ([]int, error) {
+	spheres, err := G.spheres(n)
+	if err != nil {
+		return nil, err
+	}
+	sizes := make([]int, len(spheres))
+	for i, sphere := range spheres {
+		sizes[i] = len(sphere)
+	}
+	return sizes, nil
+}
+
+// GrowthSeries returns the growth series sum_n s_n t^n of G, where s_n is the number of elements of word length n,
+// as a rational function num(t) / den(t) given by coefficient slices (constant term first), see fsa.DFA.GrowthSeries
+// This needs a regular language with exactly one geodesic word per element, which we have for trivial, free, free abelian
+// and cyclic presentations, and presentations with an automatic structure (see ComputeAutomaticStructure)
+// Otherwise it returns ErrNoRegularNormalForm
+func (G *GroupPresentation) GrowthSeries() (num, den []*big.Int, err error) {
+	d, ok := G.geodesicNormalForms()
+	if !ok {
+		return nil, nil, ErrNoRegularNormalForm
+	}
+	num, den = d.GrowthSeries()
+	return num, den, nil
+}
+
+// an automaton accepting one geodesic word for each element of G, if we know one
+func (G *GroupPresentation) geodesicNormalForms() (*fsa.DFA, bool) {
+	k := fsa.GeneratorSymbols(G.gen)
+	switch {
+	case G.AutomaticStructure() != nil:
+		return G.AutomaticStructure().acceptor, true
+	case G.hasClass(Trivial):
+		d := fsa.NewDFA(k)
+		d.SetAccept(0, true)
+		return d, true
+	case G.hasClass(Free):
+		return freelyReducedWords(G.gen), true
+	case G.hasClass(FreeAbelian):
+		return orderedPowers(G.gen), true
+	case G.gen == 1: //one-relator presentations like <a | a^n> don't get the Cyclic class
+		return cyclicGeodesics(G.cyclicOrder()), true
+	}
+	return nil, false
+}
+
+// the freely reduced words, where state a+1 means that the last letter was the symbol a
+func freelyReducedWords(generators int) *fsa.DFA {
+	k := fsa.GeneratorSymbols(generators)
+	d := fsa.NewDFA(k)
+	d.SetAccept(0, true)
+	for range k {
+		d.AddState(true)
+	}
+	for s := range k + 1 {
+		for a := range k {
+			if s == 0 || fsa.InverseSymbol(s-1) != a {
+				d.SetTransition(s, a, a+1)
+			}
+		}
+	}
+	return d
+}
+
+// the words x_0^a_0 x_1^a_1 ... x_{n-1}^a_{n-1}, where state a+1 means that the last letter was the symbol a
+func orderedPowers(generators int) *fsa.DFA {
+	k := fsa.GeneratorSymbols(generators)
+	d := fsa.NewDFA(k)
+	d.SetAccept(0, true)
+	for range k {
+		d.AddState(true)
+	}
+	for s := range k + 1 {
+		for a := range k {
+			if s == 0 || s-1 == a || a/2 > (s-1)/2 {
+				d.SetTransition(s, a, a+1)
+			}
+		}
+	}
+	return d
+}
+
+// the words x^i with 0 <= i <= order/2 and x^-i with 0 < i < order/2, or all powers of x if order is 0
+func cyclicGeodesics(order int) *fsa.DFA {
+	d := fsa.NewDFA(2)
+	d.SetAccept(0, true)
+	if order == 0 {
+		d.AddState(true)
+		d.AddState(true)
+		d.SetTransition(0, 0, 1)
+		d.SetTransition(1, 0, 1)
+		d.SetTransition(0, 1, 2)
+		d.SetTransition(2, 1, 2)
+		return d
+	}
+	for _, a := range []int{0, 1} {
+		s := 0
+		for i := 1; 2*i < order || (a == 0 && 2*i == order); i++ {
+			t := d.AddState(true)
+			d.SetTransition(s, a, t)
+			s = t
+		}
+	}
+	return d
+}
@@ -0,0 +1,115 @@
+package presentation_test
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestSphereSizes(t *testing.T) {
+	free, _ := p.NewFreeGroup(2)
+	z2, _ := p.NewFreeAbelianGroup(2)
+	z5, _ := p.NewGroupPresentation(1, p.NewWordSet(wordsOf([]RawWord{{{0, 10}}, {{0, 15}}}))) //a cyclic presentation, unlike <a | a^5>, see below
+	s3, _ := p.NewGroupPresentation(2, p.NewWordSet(wordsOf([]RawWord{{{0, 2}}, {{1, 3}}, {{0, 1}, {1, 1}, {0, 1}, {1, 1}}})))
+	if _, err := s3.ComputeAutomaticStructure(100); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		G    *p.GroupPresentation
+		want []int
+		num  []int64
+		den  []int64
+	}{
+		{"free group", free, []int{1, 4, 12, 36}, []int64{1, 1}, []int64{1, -3}},
+		{"Z^2", z2, []int{1, 4, 8, 12}, []int64{1, 2, 1}, []int64{1, -2, 1}},
+		{"Z/5", z5, []int{1, 2, 2, 0}, []int64{1, 2, 2}, []int64{1}},
+		{"S3", s3, []int{1, 3, 2, 0}, []int64{1, 3, 2}, []int64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.G.SphereSizes(len(tt.want) - 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SphereSizes = %v, want %v", got, tt.want)
+			}
+			ball, _ := tt.G.Ball(len(tt.want) - 1)
+			total := 0
+			for _, s := range tt.want {
+				total += s
+			}
+			if len(ball) != total {
+				t.Errorf("ball has %v elements, want %v", len(ball), total)
+			}
+			num, den, err := tt.G.GrowthSeries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalCoefficients(num, tt.num) || !equalCoefficients(den, tt.den) {
+				t.Errorf("GrowthSeries = %v / %v, want %v / %v", num, den, tt.num, tt.den)
+			}
+		})
+	}
+
+	if _, err := free.SphereSizes(-1); err != p.ErrNegativeRadius {
+		t.Errorf("got error %v for a negative radius", err)
+	}
+	bs, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}})}))
+	if _, _, err := bs.GrowthSeries(); err != p.ErrNoRegularNormalForm {
+		t.Errorf("got error %v, want ErrNoRegularNormalForm", err)
+	}
+}
+
+// Reduce only solves the word problem for these one-relator presentations, without normal forms, e.g. a and a^-4 reduce to different words in <a | a^5>
+func TestSphereSizesOneRelator(t *testing.T) {
+	tests := []struct {
+		name string
+		gen  int
+		rel  RawWord
+		want []int
+	}{
+		{"Z/5", 1, RawWord{{0, 5}}, []int{1, 2, 2, 0, 0}},
+		{"Z^2", 2, RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}}, []int{1, 4, 8, 12, 16}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet([]p.Word{p.NewWord(tt.rel)}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := G.SphereSizes(len(tt.want) - 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SphereSizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// only free reduction applies to <a, b | a^2, b^3>, so elements can't be told apart
+	G, err := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{0, 2}}), p.NewWord(RawWord{{1, 3}})}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := G.ReduceWithInfo(p.EmptyWord()); r.Guarantee != p.Sound {
+		t.Fatalf("got solver %v with guarantee %v", r.Solver, r.Guarantee)
+	}
+	if _, err := G.SphereSizes(2); !errors.Is(err, p.ErrWordProblemUnsolved) {
+		t.Errorf("SphereSizes gave error %v, want ErrWordProblemUnsolved", err)
+	}
+	if _, err := G.Ball(2); !errors.Is(err, p.ErrWordProblemUnsolved) {
+		t.Errorf("Ball gave error %v, want ErrWordProblemUnsolved", err)
+	}
+}
+
+func equalCoefficients(got []*big.Int, want []int64) bool {
+	return slices.EqualFunc(got, want, func(x *big.Int, y int64) bool { return x.Cmp(big.NewInt(y)) == 0 })
+}
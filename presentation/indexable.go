@@ -0,0 +1,72 @@
+package presentation
+
+// Word utilities that work on any Indexable, so that Words, tree words (word.Word), SLPs and user-defined sequences can share them
+// Since we know nothing about the letters, the functions take what they need (inverses, orderings, concatenation) as arguments
+// For the [2]int letters (generator, ±1) returned by the At methods of this library, use InvLetter and LessLetter
+
+// inverse of a (generator, ±1) letter
+func InvLetter(x [2]int) [2]int { return [2]int{x[0], -x[1]} }
+
+// order on (generator, ±1) letters: by generator, then the inverse before the generator itself
+// this matches the order of ShortLexRawWord on syllables of length 1
+func LessLetter(x, y [2]int) bool {
+	if x[0] != y[0] {
+		return x[0] < y[0]
+	}
+	return x[1] < y[1]
+}
+
+// Cyclic reduction of a freely reduced w
+// first output is the cyclically reduced word
+// the second output c is the suffix of w such that the first output is c w c^-1 (same convention as CyclicReduceRawWord, up to syllables)
+// WARNING: w must be freely reduced, we only peel off letters that cancel cyclically
+func CyclicReduceIdx[T comparable, S Indexable[T, S]](w S, inv func(T) T) (S, S) {
+	n := w.Len()
+	k := 0
+	for 2*k+1 < n && w.At(k) == inv(w.At(n-1-k)) {
+		k++
+	}
+	return w.Slice(k, n-k), w.Slice(n-k, n)
+}
+
+// ShortLexIdx reports whether a < b in shortlex order, comparing letters with less
+func ShortLexIdx[T any, S Indexable[T, S], R Indexable[T, R]](a S, b R, less func(T, T) bool) bool {
+	if a.Len() != b.Len() {
+		return a.Len() < b.Len()
+	}
+	// same length: lexicographic
+	for i := range a.Len() {
+		x, y := a.At(i), b.At(i)
+		if less(x, y) {
+			return true
+		} else if less(y, x) {
+			return false
+		}
+	}
+	return false //equal
+}
+
+// Replaces the first instance of sub in w by replacement, gluing the pieces back together with concat
+// concat is where free reduction (or any other simplification) should happen, if desired
+func ReplaceSubFirstMatchIdx[T comparable, S Indexable[T, S]](w, sub, replacement S, concat func(S, S) S) S {
+	index, exists := KMPSubFirstMatchIdx(sub, w)
+	if !exists {
+		return w //nothing to change
+	}
+	return concat(concat(w.Slice(0, index), replacement), w.Slice(index+sub.Len(), w.Len()))
+}
+
+// checks if the freely reduced w is conjugate to a proper power, see CheckIfPowerRawWord
+func CheckIfPowerIdx[T comparable, S Indexable[T, S]](w S, inv func(T) T) bool {
+	r, _ := CyclicReduceIdx(w, inv)
+	return KMPCheckRepeatsIdx(r)
+}
+
+// Finds the primitive root of the cyclic reduction of the freely reduced w, see FindPrimitiveRootRawWord
+// outputs are the primitive root, its exponent, whether it is non-trivial, and the conjugator c from CyclicReduceIdx
+// so that w = c^-1 root^k c
+func FindPrimitiveRootIdx[T comparable, S Indexable[T, S]](w S, inv func(T) T) (S, int, bool, S) {
+	r, c := CyclicReduceIdx(w, inv)
+	root, exp, ok := KMPFindPrimitiveRootIdx(r)
+	return root, exp, ok, c
+}
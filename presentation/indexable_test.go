@@ -0,0 +1,109 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+	"github.com/geometricgrouptheorydev/groups-in-go/word"
+)
+
+// a user-defined Indexable, to check that the generic functions don't need anything from this library
+type text string
+
+func (s text) At(i int) byte       { return s[i] }
+func (s text) Len() int            { return len(s) }
+func (s text) Slice(i, j int) text { return s[i:j] }
+
+// the generic KMP functions should agree on every kind of word
+func TestKMPSearchSubIdx(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	randomWord := func(n int) RawWord {
+		w := RawWord{}
+		for range r.IntN(n) {
+			w = append(w, [2]int{r.IntN(2), r.IntN(5) - 2})
+		}
+		return p.ReduceRawWord(w)
+	}
+	for range 300 {
+		sub, whole := randomWord(3), randomWord(10)
+		want := p.KMPSearchSubIdx(p.NewWord(sub), p.NewWord(whole))
+		gots := [][]int{
+			p.KMPSearchSubIdx(word.FromRaw(sub), word.FromRaw(whole)),
+			p.KMPSearchSubIdx(p.NewSLP(sub), p.NewSLP(whole)),
+			p.KMPSearchSubIdx(p.NewWord(sub), p.NewSLP(whole)), //mixing types is fine
+		}
+		for _, got := range gots {
+			if !slices.Equal(got, want) {
+				t.Fatalf("KMPSearchSubIdx(%v, %v) = %v, want %v", sub, whole, got, want)
+			}
+		}
+		if len(sub) == 0 {
+			continue //the empty word also matches at the end, which KMPSearchSubIdx doesn't report
+		}
+		if i, ok := p.SubRawWordFirstMatch(sub, whole); ok != (len(want) > 0) || (ok && i != want[0]) {
+			t.Fatalf("SubRawWordFirstMatch(%v, %v) = %v, %v but KMP found %v", sub, whole, i, ok, want)
+		}
+	}
+}
+
+func TestCyclicReduceIdx(t *testing.T) {
+	tests := []RawWord{
+		{{1, 2}, {3, 4}, {5, 6}},
+		{{0, 7}, {3, 4}, {2, -4}, {0, -7}},
+		{{5, 5}, {4, -2}, {0, 8}, {3, 4}, {2, -4}, {0, -7}, {4, 2}, {5, -5}},
+		{{0, 2}, {1, 1}, {0, -2}},
+	}
+	for _, in := range tests {
+		wantReduced, wantConj := p.CyclicReduceRawWord(in)
+		gotReduced, gotConj := p.CyclicReduceIdx(p.NewWord(in), p.InvLetter)
+		if !p.EqualWord(gotReduced, p.NewWord(wantReduced)) || !p.EqualWord(p.ReduceWord(gotConj), p.NewWord(p.ReduceRawWord(wantConj))) {
+			t.Fatalf("CyclicReduceIdx(%v) = %v, %v want %v, %v", in, gotReduced, gotConj, wantReduced, wantConj)
+		}
+		treeReduced, _ := p.CyclicReduceIdx(word.FromRaw(in), p.InvLetter)
+		if !word.Equal(treeReduced, word.FromRaw(wantReduced)) {
+			t.Fatalf("CyclicReduceIdx on tree word %v = %v want %v", in, treeReduced.Raw(), wantReduced)
+		}
+	}
+}
+
+func TestFindPrimitiveRootIdx(t *testing.T) {
+	root, exp, ok := p.KMPFindPrimitiveRootIdx(text("abcabcabc"))
+	if root != "abc" || exp != 3 || !ok {
+		t.Fatalf("KMPFindPrimitiveRootIdx(abcabcabc) = %v, %v, %v", root, exp, ok)
+	}
+	// (ab^2)^(10^6) as a tree word, whose letters are never expanded into a slice
+	huge := word.Pow(word.FromRaw(RawWord{{0, 1}, {1, 2}}), 1000000)
+	treeRoot, exp, ok, _ := p.FindPrimitiveRootIdx(huge, p.InvLetter)
+	if !word.Equal(treeRoot, word.FromRaw(RawWord{{0, 1}, {1, 2}})) || exp != 1000000 || !ok {
+		t.Fatalf("FindPrimitiveRootIdx((ab^2)^(10^6)) = %v, %v, %v", treeRoot.Raw(), exp, ok)
+	}
+	w := RawWord{{1, 2}, {3, 4}, {1, 1}, {1, 2}, {1, -1}, {3, -2}, {3, 6}}
+	wantRoot, wantExp, wantOk := p.FindPrimitiveRootRawWord(w)
+	gotRoot, gotExp, gotOk := p.FindPrimitiveRootWord(p.NewWord(w))
+	if !p.EqualWord(gotRoot, p.NewWord(wantRoot)) || gotExp != wantExp || gotOk != wantOk {
+		t.Fatalf("FindPrimitiveRootWord(%v) = %v, %v, %v want %v, %v, %v", w, gotRoot, gotExp, gotOk, wantRoot, wantExp, wantOk)
+	}
+}
+
+func TestKMPFindRepeats(t *testing.T) {
+	got := p.KMPFindRepeats([]byte("aaaa"))
+	want := []p.Repeat[string]{{Sub: "aaaa", Reps: 1}, {Sub: "a", Reps: 4}, {Sub: "aa", Reps: 2}}
+	if len(got) != len(want) {
+		t.Fatalf("KMPFindRepeats(aaaa) = %v want %v", got, want)
+	}
+	for i := range got {
+		if string(got[i].Sub) != want[i].Sub || got[i].Reps != want[i].Reps {
+			t.Fatalf("KMPFindRepeats(aaaa) = %v want %v", got, want)
+		}
+	}
+}
+
+func TestShortLexIdx(t *testing.T) {
+	a := p.NewWord(RawWord{{0, 2}, {1, -1}})
+	b := word.FromRaw(RawWord{{0, 2}, {1, 1}})
+	if !p.ShortLexIdx(a, b, p.LessLetter) || p.ShortLexIdx(b, a, p.LessLetter) {
+		t.Fatalf("expected %v < %v in shortlex order", a, b.Raw())
+	}
+}
@@ -1,31 +1,54 @@
 package presentation
 
-// Here are some helper functions that use the KMP prefix function on slices
+// Here are some helper functions that use the KMP prefix function on sequences
 // This will allow multiple functions to be have O(n) time complexity rather than O(n^2)
 
-//First, we set up the following interface
-type Indexable[T any] interface {
-    At(i int) T
-    Len() int
-    Slice(i, j int) Indexable[T]
+// First, we set up the following interface
+// S is the type returned by Slice, usually the type implementing the interface itself
+// e.g. Word is an Indexable[[2]int, Word], *word.Word is an Indexable[[2]int, *word.Word] and SLP is an Indexable[[2]int, SLP]
+type Indexable[T any, S any] interface {
+	At(i int) T
+	Len() int
+	Slice(i, j int) S
 }
 
-// This first batch of functions use the standard slice indexing
-// This batch left here as legacy code that is no longer used by the package
-// See the second batch below for the functions used by the package
+// IndexableSlice lets plain slices be used as Indexables
+type IndexableSlice[T any] []T
 
-// For each i := range w finds the length of the longest prefix of w[i] that is also a suffix
-func KMPPrefixFunction[T comparable](w []T) []int {
-	pi := make([]int, len(w)) // pi[0] is always 0 so we won't change that in the loop
-	for i := 1; i < len(w); i++ {
+func (s IndexableSlice[T]) At(i int) T                       { return s[i] }
+func (s IndexableSlice[T]) Len() int                         { return len(s) }
+func (s IndexableSlice[T]) Slice(i, j int) IndexableSlice[T] { return s[i:j] }
+
+// indexFunc turns an index function and a length into an Indexable
+type indexFunc[T any] struct {
+	at     func(int) T
+	offset int
+	length int
+}
+
+func (f indexFunc[T]) At(i int) T { return f.at(f.offset + i) }
+func (f indexFunc[T]) Len() int   { return f.length }
+func (f indexFunc[T]) Slice(i, j int) indexFunc[T] {
+	return indexFunc[T]{at: f.at, offset: f.offset + i, length: j - i}
+}
+
+// The generic versions below work on any Indexable and all other KMP functions call them
+// Note: At should be idempotent (return the same value for the same index).
+
+// For each i := range w.Len() finds the length of the longest prefix of w[:i+1] that is also a suffix
+func KMPPrefixFunctionIdx[T comparable, S Indexable[T, S]](w S) []int {
+	pi := make([]int, w.Len()) // pi[0] is always 0 so we won't change that in the loop
+	for i := 1; i < w.Len(); i++ {
 		// Initialize j with the value from the previous position
 		j := pi[i-1]
+		// Cache current value to avoid redundant calls to the At accessor
+		valI := w.At(i)
 		// Continue updating j until a match is found or j becomes 0
-		for j > 0 && w[i] != w[j] {
+		for j > 0 && valI != w.At(j) {
 			j = pi[j-1]
 		}
 		// If a match is found, increment the length of the common prefix/suffix
-		if w[i] == w[j] {
+		if valI == w.At(j) {
 			j++
 		}
 		// Update the Prefix Function value for the current position
@@ -34,126 +57,108 @@ func KMPPrefixFunction[T comparable](w []T) []int {
 	return pi
 }
 
-// Returns the indices where each occurrence of sub appears in whole
-func KMPSearchSub[T comparable](sub, whole []T) []int {
+// calls found on the start index of each occurrence of sub in whole, stopping early if found returns false
+func kmpScan[T comparable, S Indexable[T, S], R Indexable[T, R]](sub S, whole R, found func(int) bool) {
+	lenSub, lenWhole := sub.Len(), whole.Len()
 	// Take care of the trivial cases
-	if len(sub) == 0 {
-		everywhere := make([]int, len(whole))
-		for i := range whole {
-			everywhere[i] = i
+	if lenSub == 0 {
+		for i := range lenWhole {
+			if !found(i) {
+				return
+			}
 		}
-		return everywhere
-	} else if len(sub) > len(whole) {
-		nowhere := make([]int, 0)
-		return nowhere
+		return
+	} else if lenSub > lenWhole {
+		return
 	}
-	occurrences := make([]int, 0)
-	pi := KMPPrefixFunction(sub)
+	pi := KMPPrefixFunctionIdx(sub)
 	j := 0 // current match length
-	for i := range whole {
-		for j > 0 && whole[i] != sub[j] {
+	for i := range lenWhole {
+		// Cache current value to avoid redundant calls to the At accessor
+		valI := whole.At(i)
+		for j > 0 && valI != sub.At(j) {
 			j = pi[j-1]
 		}
-		if whole[i] == sub[j] {
+		if valI == sub.At(j) {
 			j++
 		}
-		if j == len(sub) {
-			occurrences = append(occurrences, i-j+1)
+		if j == lenSub {
+			if !found(i - j + 1) {
+				return
+			}
 			j = pi[j-1]
 		}
 	}
-	return occurrences
 }
 
+// Returns the indices where each occurrence of sub appears in whole
+// sub and whole may be different Indexable types as long as their letters are of the same type
+func KMPSearchSubIdx[T comparable, S Indexable[T, S], R Indexable[T, R]](sub S, whole R) []int {
+	occurrences := make([]int, 0)
+	kmpScan(sub, whole, func(i int) bool {
+		occurrences = append(occurrences, i)
+		return true
+	})
+	return occurrences
+}
 
 // Returns the index of the start of the first match of sub in whole and true if there is a match
 // Otherwise, return -1 and false (-1 is used as that is not a valid index in Go, always check the boolean to avoid panics)
-// This function can also be used to find any match at all
-func KMPSubFirstMatch[T comparable](sub, whole []T) (int, bool) {
-	// Take care of the trivial cases
-	if len(sub) == 0 {
-		return 0, true
-	} else if len(sub) > len(whole) {
-		return -1, false
-	}
-	pi := KMPPrefixFunction(sub)
-	j := 0 // current match length
-	for i := range whole {
-		for j > 0 && whole[i] != sub[j] {
-			j = pi[j-1]
-		}
-		if whole[i] == sub[j] {
-			j++
-		}
-		if j == len(sub) {
-			return i - j + 1, true
-		}
+func KMPSubFirstMatchIdx[T comparable, S Indexable[T, S], R Indexable[T, R]](sub S, whole R) (int, bool) {
+	if sub.Len() == 0 {
+		return 0, true //the empty word is everywhere, even in the empty word
 	}
-	return -1, false
+	index := -1
+	kmpScan(sub, whole, func(i int) bool {
+		index = i
+		return false
+	})
+	return index, index >= 0
 }
 
-// lists w as a subslice repeated several times, ordered from largest to lowest
-// the trivial (w, 1) is always returned
-func KMPFindRepeats[T comparable](w []T) []struct {
-	Sub  []T
+// A repeat of w is a subsequence Sub such that w is Sub repeated Reps times
+type Repeat[S any] struct {
+	Sub  S
 	Reps int
-} {
-	repeats := []struct {
-		Sub  []T
-		Reps int
-	}{
-		{
-			Sub:  w,
-			Reps: 1,
-		},
-	}
-	pi := KMPPrefixFunction(w)
-	n := len(w)
+}
+
+// lists w as a subsequence repeated several times, the trivial (w, 1) first and then by increasing length of the subsequence
+func KMPFindRepeatsIdx[T comparable, S Indexable[T, S]](w S) []Repeat[S] {
+	repeats := []Repeat[S]{{Sub: w, Reps: 1}}
+	pi := KMPPrefixFunctionIdx(w)
+	length := w.Len()
+	n := length
 	for n > 0 {
 		// Initialize to the longest known prefix that is also a suffix (also known as a border)
 		// if w is v repeated, then v is repeated
 		// we check each border in order of decreasing length in the next loop iterations (borders of borders are borders)
 		n = pi[n-1]
 		// k is the number of positions between the start of the prefix and the start of its repetition as a suffix
-		// therefore k is the period of the slice
-		k := len(w) - n
-		// len(w) needs to be a multiple of k to have a chance of w being w[:n] repeated
-		// k also needs to be smaller than w lest we don't have a period at all!
+		// therefore k is the period of the sequence
+		k := length - n
+		// length needs to be a multiple of k to have a chance of w being w[:k] repeated
+		// k also needs to be smaller than the length lest we don't have a period at all!
 		// this suffices because w[i] = w[i + k]
-		if k < len(w) && len(w)%k == 0 {
-			rpt := struct {
-				Sub  []T
-				Reps int
-			}{
-				Sub:  w[:n],
-				Reps: len(w) / n,
-			}
-			repeats = append(repeats, rpt)
+		if k < length && length%k == 0 {
+			repeats = append(repeats, Repeat[S]{Sub: w.Slice(0, k), Reps: length / k})
 		}
 	}
 	return repeats
 }
 
-// Checks if w is a slice that is a repeated subslice
-func KMPCheckRepeats[T comparable](w []T) bool {
-	pi := KMPPrefixFunction(w)
-	n := len(w)
-	for n > 0 {
-		// Initialize to the longest known prefix that is also a suffix (also known as a border)
-		// if w is v repeated, then v is repeated
-		// we check each border in order of decreasing length in the next loop iterations (borders of borders are borders)
-		n = pi[n-1]
-		// k is the number of positions between the start of the prefix and the start of its repetition as a suffix
-		// therefore k is the period of the slice
-		k := len(w) - n
-		// len(w) needs to be a multiple of k to have a chance of w being w[:n] repeated
-		// k also needs to be smaller than w lest we don't have a period at all!
-		// this suffices because w[i] = w[i + k]
-		if k < len(w) && len(w)%k == 0 {
-			return true
-		}
+// Checks if w is a sequence that is a repeated subsequence
+func KMPCheckRepeatsIdx[T comparable, S Indexable[T, S]](w S) bool {
+	length := w.Len()
+	if length == 0 {
+		return false
 	}
-	return false
+	pi := KMPPrefixFunctionIdx(w)
+	n := pi[length-1]
+	k := length - n
+
+	// A string is a power of some substring if and only if
+	// the length is a multiple of the smallest period k.
+	return n > 0 && length%k == 0
 }
 
 // A root of a word w is some subword v such that w = v^k for some positive k
@@ -162,127 +167,99 @@ func KMPCheckRepeats[T comparable](w []T) bool {
 // We call this the primitive root, the first output of this function
 // The second output gives the k for that primitive root
 // The third output is true exactly when the primitive root is non-trivial
-func KMPFindPrimitiveRoot[T comparable](w []T) ([]T, int, bool) {
-	n := len(w)
+func KMPFindPrimitiveRootIdx[T comparable, S Indexable[T, S]](w S) (S, int, bool) {
+	n := w.Len()
 	if n == 0 {
-		return []T{}, 0, false
+		return w, 0, false
 	}
 
-	pi := KMPPrefixFunction(w)
+	pi := KMPPrefixFunctionIdx(w)
 	r := n - pi[n-1] //primitive root length
 
 	// If r divides n and r < n, the word is (non-trivially) periodic
 	if n%r == 0 {
 		exp := n / r
-		return w[:r], exp, exp > 1
+		return w.Slice(0, r), exp, exp > 1
 	} else {
 		return w, 1, false
 	}
 }
 
-// This second batch of functions uses indexing determined by an index function provided by the user
+// This batch of functions use the standard slice indexing, they are thin wrappers around the generic versions
+
+// For each i := range w finds the length of the longest prefix of w[i] that is also a suffix
+func KMPPrefixFunction[T comparable](w []T) []int {
+	return KMPPrefixFunctionIdx(IndexableSlice[T](w))
+}
+
+// Returns the indices where each occurrence of sub appears in whole
+func KMPSearchSub[T comparable](sub, whole []T) []int {
+	return KMPSearchSubIdx(IndexableSlice[T](sub), IndexableSlice[T](whole))
+}
+
+// Returns the index of the start of the first match of sub in whole and true if there is a match
+// Otherwise, return -1 and false (-1 is used as that is not a valid index in Go, always check the boolean to avoid panics)
+// This function can also be used to find any match at all
+func KMPSubFirstMatch[T comparable](sub, whole []T) (int, bool) {
+	return KMPSubFirstMatchIdx(IndexableSlice[T](sub), IndexableSlice[T](whole))
+}
+
+// lists w as a subslice repeated several times, the trivial (w, 1) first and then by increasing length of the subslice
+// This keeps its original return type, KMPFindRepeatsIdx returns the same as a []Repeat
+func KMPFindRepeats[T comparable](w []T) []struct {
+	Sub  []T
+	Reps int
+} {
+	repeats := KMPFindRepeatsIdx(IndexableSlice[T](w))
+	out := make([]struct {
+		Sub  []T
+		Reps int
+	}, len(repeats))
+	for i, r := range repeats {
+		out[i].Sub, out[i].Reps = r.Sub, r.Reps
+	}
+	return out
+}
+
+// Checks if w is a slice that is a repeated subslice
+func KMPCheckRepeats[T comparable](w []T) bool {
+	return KMPCheckRepeatsIdx(IndexableSlice[T](w))
+}
+
+// See KMPFindPrimitiveRootIdx
+func KMPFindPrimitiveRoot[T comparable](w []T) ([]T, int, bool) {
+	root, exp, ok := KMPFindPrimitiveRootIdx(IndexableSlice[T](w))
+	return root, exp, ok
+}
+
+// This batch of functions uses indexing determined by an index function provided by the user, again wrapping the generic versions
 
 // Input: Method value on a composite data type and length of the composite data (which need not be the "typical" slice length)
-// e.g. for Words, it would correspond to KMPPrefixFunctionAt(w.At, Len(w))
+// e.g. for Words, it would correspond to KMPPrefixFunctionAt(w.At, w.Len())
 // Output: for each i := range length finds the length of the longest prefix of the first at(i) entries that is also a suffix
 // Note: 'at' should be idempotent (return the same value for the same index).
 func KMPPrefixFunctionAt[T comparable](at func(int) T, length int) []int {
-	pi := make([]int, length) // pi[0] is always 0 so we won't change that in the loop
-	for i := 1; i < length; i++ {
-		// Initialize j with the value from the previous position
-		j := pi[i-1]
-		// Cache current value to avoid redundant calls to the 'at' accessor
-		valI := at(i)
-		// Continue updating j until a match is found or j becomes 0
-		for j > 0 && valI != at(j) {
-			j = pi[j-1]
-		}
-		// If a match is found, increment the length of the common prefix/suffix
-		if valI == at(j) {
-			j++
-		}
-		// Update the Prefix Function value for the current position
-		pi[i] = j
-	}
-	return pi
+	return KMPPrefixFunctionIdx(indexFunc[T]{at: at, length: length})
 }
 
 // Input: Method values on two composite data type and their lengths
-// e.g. for Words, it would correspond to KMPSearchSubAt(sub.At, whole.At, Len(sub), Len(whole))
+// e.g. for Words, it would correspond to KMPSearchSubAt(sub.At, whole.At, sub.Len(), whole.Len())
 // Returns the indices where each occurrence of sub appears in whole
 func KMPSearchSubAt[T comparable](subAt, wholeAt func(int) T, lenSub, lenWhole int) []int {
-	// Take care of the trivial cases
-	if lenSub == 0 {
-		everywhere := make([]int, lenWhole)
-		for i := range lenWhole {
-			everywhere[i] = i
-		}
-		return everywhere
-	} else if lenSub > lenWhole {
-		nowhere := make([]int, 0)
-		return nowhere
-	}
-	occurrences := make([]int, 0)
-	pi := KMPPrefixFunctionAt(subAt, lenSub)
-	j := 0 // current match length
-	for i := range lenWhole {
-		// Cache current value to avoid redundant calls to the 'at' accessor
-		valI := wholeAt(i)
-		for j > 0 && valI != subAt(j) {
-			j = pi[j-1]
-		}
-		if valI == subAt(j) {
-			j++
-		}
-		if j == lenSub {
-			occurrences = append(occurrences, i-j+1)
-			j = pi[j-1]
-		}
-	}
-	return occurrences
+	return KMPSearchSubIdx(indexFunc[T]{at: subAt, length: lenSub}, indexFunc[T]{at: wholeAt, length: lenWhole})
 }
 
 // Input: Method values on two composite data type and their lengths
-// e.g. for Words, it would correspond to KMPSubFirstMatchAt(sub.At, whole.At, Len(sub), Len(whole))
+// e.g. for Words, it would correspond to KMPSubFirstMatchAt(sub.At, whole.At, sub.Len(), whole.Len())
 // Returns the index of the start of the first match of sub in whole and true if there is a match
 // Otherwise, return -1 and false (-1 is used as that is not a valid index in Go, always check the boolean to avoid panics)
 func KMPSubFirstMatchAt[T comparable](subAt, wholeAt func(int) T, lenSub, lenWhole int) (int, bool) {
-	// Take care of the trivial cases
-	if lenSub == 0 {
-		return 0, true
-	} else if lenSub > lenWhole {
-		return -1, false
-	}
-	pi := KMPPrefixFunctionAt(subAt, lenSub)
-	j := 0 // current match length
-	for i := range lenWhole {
-		// Cache current value to avoid redundant calls to the 'at' accessor
-		valI := wholeAt(i)
-		for j > 0 && valI != subAt(j) {
-			j = pi[j-1]
-		}
-		if valI == subAt(j) {
-			j++
-		}
-		if j == lenSub {
-			return i - j + 1, true
-		}
-	}
-	return -1, false
+	return KMPSubFirstMatchIdx(indexFunc[T]{at: subAt, length: lenSub}, indexFunc[T]{at: wholeAt, length: lenWhole})
 }
 
 // Input: Method value on a composite data type and length of the composite data
-// e.g. for Words, it would correspond to KMPCheckRepeatsAtAt(w.At, Len(w))
+// e.g. for Words, it would correspond to KMPCheckRepeatsAt(w.At, w.Len())
 // Checks if w is a slice that is a repeated subslice
 func KMPCheckRepeatsAt[T comparable](at func(int) T, length int) bool {
-	if length == 0 {
-		return false
-	}
-	pi := KMPPrefixFunctionAt(at, length)
-	n := pi[length-1]
-	k := length - n
-	
-	// A string is a power of some substring if and only if 
-	// the length is a multiple of the smallest period k.
-	return n > 0 && length%k == 0
-}
\ No newline at end of file
+	return KMPCheckRepeatsIdx(indexFunc[T]{at: at, length: length})
+}
@@ -0,0 +1,163 @@
+package presentation
+
+import "context"
+
+// The Knuth-Bendix completion procedure turns a rewriting system into an equivalent confluent one, if it finishes
+// Confluent means that every word rewrites to the same irreducible word whatever rules we apply, so irreducible words are normal forms
+// Rules are always oriented from the bigger side to the smaller one for a WordOrder, which makes rewriting terminate
+// Whenever two left hand sides overlap (uv and vw) or one contains the other, the word uvw can be rewritten in two ways: that's a critical pair
+// We rewrite both results, and if they differ we get a new rule, until all critical pairs resolve
+
+// a rule on expanded words, or an equation before it is oriented
+// when tracking proofs, proof writes lhs rhs^-1 as a product of conjugates of relators in the group (see certificate.go)
+type kbRule struct {
+	lhs, rhs RawWord
+	proof    proof
+}
+
+type knuthBendix struct {
+	order    WordOrder
+	rules    []kbRule
+	rewriter *Rewriter //nil when the rules changed since it was compiled
+	// when tracking proofs, the group words of monoid words and the rewriter keeping track of proofs, nil when the rules changed
+	group  func(RawWord) RawWord
+	prover *provingRewriter
+}
+
+func (kb *knuthBendix) system() RewritingSystem {
+	R := RewritingSystem{LHS: make([]RawWord, len(kb.rules)), RHS: make([]RawWord, len(kb.rules))}
+	for i, r := range kb.rules {
+		R.LHS[i], R.RHS[i] = syllableForm(r.lhs), syllableForm(r.rhs)
+	}
+	return R
+}
+
+// reduces w with the rules, along with a proof of w reduced^-1 when tracking proofs
+func (kb *knuthBendix) reduce(w RawWord) (RawWord, proof) {
+	if kb.group != nil {
+		if kb.prover == nil {
+			kb.prover = newProvingRewriter(kb.rules, kb.group)
+		}
+		return kb.prover.rewrite(w)
+	}
+	if kb.rewriter == nil {
+		kb.rewriter, _ = kb.system().Compile(LeftmostFirst) //LHS and RHS always have the same length
+	}
+	return expandRawWord(kb.rewriter.Rewrite(w)), nil
+}
+
+// the critical pairs of the rules r and s (in that order): words rewritten by r on the left and s on the right
+func (kb *knuthBendix) criticalPairs(r, s kbRule) []kbRule {
+	pairs := make([]kbRule, 0)
+	// a proper suffix of r.lhs is a prefix of s.lhs: r.lhs = xy, s.lhs = yz with x, y nonempty
+	for k := 1; k < len(r.lhs); k++ {
+		y := r.lhs[len(r.lhs)-k:]
+		if k > len(s.lhs) {
+			break
+		} else if !equalSlices(y, s.lhs[:k]) {
+			continue
+		}
+		x, z := r.lhs[:len(r.lhs)-k], s.lhs[k:]
+		pair := kbRule{lhs: ConcatRawWord(r.rhs, z), rhs: ConcatRawWord(x, s.rhs)}
+		if kb.group != nil { //r.rhs z s.rhs^-1 x^-1 = (r.rhs y^-1 x^-1) (x y z s.rhs^-1 x^-1)
+			pair.proof = r.proof.inverse().concat(s.proof.conjugate(kb.group(x)))
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// the two sides of an equation, reduced and oriented, or false if they are equal
+func (kb *knuthBendix) orient(eq kbRule) (kbRule, bool) {
+	u, pu := kb.reduce(eq.lhs)
+	v, pv := kb.reduce(eq.rhs)
+	var p proof
+	if kb.group != nil { //u v^-1 = (u eq.lhs^-1) (eq.lhs eq.rhs^-1) (eq.rhs v^-1)
+		p = pu.inverse().concat(eq.proof).concat(pv)
+	}
+	switch kb.order.Compare(u, v) {
+	case 1:
+		return kbRule{lhs: u, rhs: v, proof: p}, true
+	case -1:
+		return kbRule{lhs: v, rhs: u, proof: p.inverse()}, true
+	}
+	return kbRule{}, false
+}
+
+// adds the rule r, removing the rules whose left hand side becomes reducible (they come back as equations) and reducing the right hand sides of the others
+func (kb *knuthBendix) add(r kbRule) []kbRule {
+	equations := make([]kbRule, 0)
+	kept := make([]kbRule, 0, len(kb.rules)+1)
+	for _, s := range kb.rules {
+		if _, ok := KMPSubFirstMatch(r.lhs, s.lhs); ok {
+			equations = append(equations, s)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+	kb.rules = append(kept, r)
+	kb.rewriter, kb.prover = nil, nil
+	for i := range kb.rules {
+		rhs, p := kb.reduce(kb.rules[i].rhs)
+		kb.rules[i].rhs = rhs
+		if kb.group != nil { //lhs rhs'^-1 = (lhs rhs^-1) (rhs rhs'^-1)
+			kb.rules[i].proof = kb.rules[i].proof.concat(p)
+		}
+	}
+	kb.rewriter, kb.prover = nil, nil
+	// overlaps of the new rule with every rule, both ways, including itself
+	for i, s := range kb.rules {
+		equations = append(equations, kb.criticalPairs(r, s)...)
+		if i < len(kb.rules)-1 { //the last rule is r itself
+			equations = append(equations, kb.criticalPairs(s, r)...)
+		}
+	}
+	return equations
+}
+
+// adds rules from the equations and the critical pairs they lead to until b stops it, and reports whether the result is confluent
+// we process equations in the order they come, so short critical pairs of early rules are resolved first
+func (kb *knuthBendix) complete(b *budget, queue []kbRule) bool {
+	for len(queue) > 0 {
+		eq := queue[0]
+		queue = queue[1:]
+		r, ok := kb.orient(eq)
+		if !ok {
+			continue //resolved
+		} else if b.stopped(r.lhs) || !b.addRule() {
+			return false
+		}
+		queue = append(queue, kb.add(r)...)
+	}
+	return true
+}
+
+// KnuthBendix runs the Knuth-Bendix completion on R, orienting rules with o
+// At most maxRules rules are ever added, and the second output is true exactly when the result is confluent
+// Even when it isn't, the result is equivalent to R and its rules are true equations, which is enough for e.g. gathering word differences (see automatic.go)
+// Rewriting freely reduces (see Rewriter), but critical pairs with the implicit rules x x^-1 -> 1 are not considered
+// so for groups, run this on the rewriting system of the monoid presentation (see NewRewritingSystem), whose words are positive
+func (R RewritingSystem) KnuthBendix(o WordOrder, maxRules int) (RewritingSystem, bool) {
+	return R.knuthBendix(rulesBudget(context.Background(), maxRules), o)
+}
+
+// KnuthBendixContext is KnuthBendix with at most limits.MaxRules rules whose left hand sides have at most limits.MaxWordLength letters
+// When the result is not confluent, the error says why it stopped: ctx.Err() or a *LimitError
+func (R RewritingSystem) KnuthBendixContext(ctx context.Context, o WordOrder, limits Limits) (RewritingSystem, bool, error) {
+	b := newBudget(ctx, limits)
+	S, confluent := R.knuthBendix(b, o)
+	return S, confluent, b.err
+}
+
+// KnuthBendix, stopping (with a result that is not confluent) when b does
+func (R RewritingSystem) knuthBendix(b *budget, o WordOrder) (RewritingSystem, bool) {
+	kb := &knuthBendix{order: o}
+	queue := make([]kbRule, 0, len(R.LHS))
+	for i := range R.LHS {
+		queue = append(queue, kbRule{lhs: expandRawWord(R.LHS[i]), rhs: expandRawWord(R.RHS[i])})
+	}
+	confluent := kb.complete(b, queue)
+	S := kb.system()
+	S.Monoid = R.Monoid
+	return S, confluent
+}
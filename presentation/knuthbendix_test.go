@@ -0,0 +1,90 @@
+package presentation_test
+
+import (
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestKnuthBendix(t *testing.T) {
+	// generators a, b and their formal inverses A = 2, B = 3, ordered a < A < b < B
+	order := p.ShortLex{Letters: p.LetterOrder{Generators: []int{0, 2, 1, 3}}}
+	tests := []struct {
+		name      string
+		rel       []RawWord
+		elements  int //number of irreducible words of length at most 6, -1 to skip
+		rewrites  [][2]RawWord
+		wantRules int
+	}{
+		{
+			name:      "Z^2",
+			rel:       []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -1}}},
+			elements:  -1,
+			rewrites:  [][2]RawWord{{{{1, 1}, {0, 1}}, {{0, 1}, {1, 1}}}, {{{3, 1}, {2, 1}, {1, 1}}, {{2, 1}}}},
+			wantRules: 8,
+		},
+		{
+			name:     "S3",
+			rel:      []RawWord{{{0, 2}}, {{1, 3}}, {{0, 1}, {1, 1}, {0, 1}, {1, 1}}},
+			elements: 6,
+			rewrites: [][2]RawWord{{{{1, 1}, {0, 1}}, {{0, 1}, {3, 1}}}, {{{2, 1}}, {{0, 1}}}, {{{1, 2}}, {{3, 1}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(2, p.NewWordSet(wordsOf(tt.rel)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			S, confluent := p.NewRewritingSystem(G, order).KnuthBendix(order, 100)
+			if !confluent {
+				t.Fatalf("KnuthBendix did not complete")
+			}
+			if tt.wantRules > 0 && len(S.LHS) != tt.wantRules {
+				t.Errorf("got %v rules, want %v", len(S.LHS), tt.wantRules)
+			}
+			for _, r := range tt.rewrites {
+				if got := S.Rewrite(r[0]); !p.EqualRawWord(got, r[1]) {
+					t.Errorf("Rewrite(%v) = %v, want %v", r[0], got, r[1])
+				}
+			}
+			if tt.elements >= 0 {
+				irreducible := 0
+				for _, w := range positiveWords(4, 6) {
+					if p.EqualRawWord(S.Rewrite(w), p.ReduceRawWord(w)) {
+						irreducible++
+					}
+				}
+				if irreducible != tt.elements {
+					t.Errorf("got %v irreducible words, want %v", irreducible, tt.elements)
+				}
+			}
+		})
+	}
+}
+
+func wordsOf(raw []RawWord) []p.Word {
+	words := make([]p.Word, len(raw))
+	for i, w := range raw {
+		words[i] = p.NewWord(w)
+	}
+	return words
+}
+
+// all positive words on the given number of generators up to length maxLen, as letters
+func positiveWords(generators, maxLen int) []RawWord {
+	words := []RawWord{{}}
+	last := words
+	for range maxLen {
+		next := make([]RawWord, 0)
+		for _, w := range last {
+			for g := range generators {
+				next = append(next, append(append(RawWord{}, w...), [2]int{g, 1}))
+			}
+		}
+		words = append(words, next...)
+		last = next
+	}
+	return words
+}
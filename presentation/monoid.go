@@ -0,0 +1,154 @@
+package presentation
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidMonoidRelation = errors.New("presentation: monoid relation uses an out-of-range generator or a negative exponent")
+
+// Monoid presentations have explicit generators and no inverses: words are positive words, i.e. RawWords whose exponents are all positive
+// Relations are pairs of positive words (u, v) meaning u = v, since we can't move everything to one side like for groups
+// Rewriting systems are monoid objects, so this is also what they are built from (see NewRewritingSystem)
+type MonoidPresentation struct {
+	gen int       //generators
+	rel [][2]Word //relations u = v
+}
+
+// Arguments: number of generators and the relations. Invalid presentations return an error.
+// Relations are stored freely reduced, which for positive words only merges adjacent syllables of the same generator
+func NewMonoidPresentation(generators int, relations [][2]Word) (*MonoidPresentation, error) {
+	if generators < 0 {
+		return nil, ErrInvalidNumGenerators
+	}
+	M := &MonoidPresentation{gen: generators, rel: make([][2]Word, 0, len(relations))}
+	for _, r := range relations {
+		for _, w := range r {
+			if M.IsValidWord(w) != nil {
+				return nil, ErrInvalidMonoidRelation
+			}
+		}
+		M.rel = append(M.rel, [2]Word{ReduceWord(r[0]), ReduceWord(r[1])})
+	}
+	return M, nil
+}
+
+// returns the number of generators of M
+func (M *MonoidPresentation) NumGenerators() int {
+	return M.gen
+}
+
+// returns (a copy of) the relations of M
+func (M *MonoidPresentation) Relations() [][2]Word {
+	return append([][2]Word{}, M.rel...)
+}
+
+// checks that w is a positive word on the generators of M
+func (M *MonoidPresentation) IsValidWord(w Word) error {
+	for i, u := range w.seq {
+		if u[0] >= M.gen || u[0] < 0 {
+			return fmt.Errorf("invalid generator %v at word index %v", u[0], i)
+		} else if u[1] < 0 {
+			return fmt.Errorf("negative exponent %v at word index %v", u[1], i)
+		}
+	}
+	return nil
+}
+
+// a monoid presentation is its own monoid presentation, see Presentation
+func (M *MonoidPresentation) MonoidPresentation() *MonoidPresentation {
+	return M
+}
+
+// The monoid presentation of a group presentation with n generators has 2n generators: generator i is x_i and generator n+i is the formal inverse X_i of x_i
+// The relations are x_i X_i = 1 and X_i x_i = 1 for each i, followed by r = 1 for each relator r written with formal inverses
+func (G *GroupPresentation) MonoidPresentation() *MonoidPresentation {
+	n := G.gen
+	rel := G.sortedRelators() //so that rewriting systems built from M don't depend on map order
+	M := &MonoidPresentation{gen: 2 * n, rel: make([][2]Word, 0, 2*n+len(rel))}
+	for i := range n {
+		M.rel = append(M.rel,
+			[2]Word{NewWord(RawWord{{i, 1}, {n + i, 1}}), EmptyWord()},
+			[2]Word{NewWord(RawWord{{n + i, 1}, {i, 1}}), EmptyWord()},
+		)
+	}
+	for _, r := range rel {
+		M.rel = append(M.rel, [2]Word{GroupToMonoidWord(r, n), EmptyWord()})
+	}
+	return M
+}
+
+// Writes a group word on n generators as a positive word, replacing x_i^-k by X_i^k where X_i is generator n+i, see GroupPresentation.MonoidPresentation
+func GroupToMonoidRawWord(w RawWord, n int) RawWord {
+	m := make(RawWord, 0, len(w))
+	for _, u := range w {
+		if u[1] < 0 {
+			m = append(m, [2]int{n + u[0], -u[1]})
+		} else {
+			m = append(m, u)
+		}
+	}
+	return ReduceRawWord(m)
+}
+
+func GroupToMonoidWord(w Word, n int) Word {
+	return NewWord(GroupToMonoidRawWord(w.seq, n))
+}
+
+// The inverse of GroupToMonoidRawWord, writing X_i^k as x_i^-k
+func MonoidToGroupRawWord(w RawWord, n int) RawWord {
+	g := make(RawWord, 0, len(w))
+	for _, u := range w {
+		if u[0] >= n {
+			g = append(g, [2]int{u[0] - n, -u[1]})
+		} else {
+			g = append(g, u)
+		}
+	}
+	return ReduceRawWord(g)
+}
+
+func MonoidToGroupWord(w Word, n int) Word {
+	return NewWord(MonoidToGroupRawWord(w.seq, n))
+}
+
+// Both GroupPresentation and MonoidPresentation are Presentations, so rewriting systems can be built from either of them
+type Presentation interface {
+	MonoidPresentation() *MonoidPresentation
+}
+
+// Builds the rewriting system of the monoid presentation of P, orienting every relation u = v from the bigger side to the smaller one for o
+// Relations whose sides are equal words are dropped
+// Note: the system is not completed, so in general rewriting with it does not give normal forms
+func NewRewritingSystem(P Presentation, o WordOrder) RewritingSystem {
+	M := P.MonoidPresentation()
+	R := RewritingSystem{Monoid: M}
+	for _, r := range M.rel {
+		switch o.Compare(r[0].seq, r[1].seq) {
+		case 1:
+			R.LHS, R.RHS = append(R.LHS, r[0].seq), append(R.RHS, r[1].seq)
+		case -1:
+			R.LHS, R.RHS = append(R.LHS, r[1].seq), append(R.RHS, r[0].seq)
+		}
+	}
+	return R
+}
+
+// The positive braid monoid on n strands, with generators s_0, ..., s_{n-2} and relations
+// s_i s_j = s_j s_i for |i - j| >= 2 and s_i s_{i+1} s_i = s_{i+1} s_i s_{i+1}
+func NewPositiveBraidMonoid(strands int) (*MonoidPresentation, error) {
+	if strands < 1 {
+		return nil, ErrInvalidNumGenerators
+	}
+	n := strands - 1
+	rel := make([][2]Word, 0)
+	for i := range n {
+		for j := i + 2; j < n; j++ {
+			rel = append(rel, [2]Word{NewWord(RawWord{{i, 1}, {j, 1}}), NewWord(RawWord{{j, 1}, {i, 1}})})
+		}
+		if i+1 < n {
+			rel = append(rel, [2]Word{NewWord(RawWord{{i, 1}, {i + 1, 1}, {i, 1}}), NewWord(RawWord{{i + 1, 1}, {i, 1}, {i + 1, 1}})})
+		}
+	}
+	return NewMonoidPresentation(n, rel)
+}
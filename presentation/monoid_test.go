@@ -0,0 +1,112 @@
+package presentation_test
+
+import (
+	"slices"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestNewMonoidPresentation(t *testing.T) {
+	tests := []struct {
+		name    string
+		gen     int
+		rel     [][2]RawWord
+		wantErr bool
+	}{
+		{
+			name: "bicyclic monoid",
+			gen:  2,
+			rel:  [][2]RawWord{{{{0, 1}, {1, 1}}, {}}},
+		},
+		{
+			name:    "negative exponent",
+			gen:     2,
+			rel:     [][2]RawWord{{{{0, 1}, {1, -1}}, {}}},
+			wantErr: true,
+		},
+		{
+			name:    "out-of-range generator",
+			gen:     2,
+			rel:     [][2]RawWord{{{{0, 1}}, {{2, 1}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel := make([][2]p.Word, 0, len(tt.rel))
+			for _, r := range tt.rel {
+				rel = append(rel, [2]p.Word{p.NewWord(r[0]), p.NewWord(r[1])})
+			}
+			M, err := p.NewMonoidPresentation(tt.gen, rel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wanted error %v got error %v", tt.wantErr, err)
+			}
+			if err == nil && (M.NumGenerators() != tt.gen || len(M.Relations()) != len(tt.rel)) {
+				t.Fatalf("got %v generators and relations %v", M.NumGenerators(), M.Relations())
+			}
+		})
+	}
+}
+
+func TestGroupMonoidPresentation(t *testing.T) {
+	// Z/3 = <a | a^3>
+	G, err := p.NewGroupPresentation(1, p.NewWordSet([]p.Word{p.NewWord(RawWord{{0, -3}})}))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	M := G.MonoidPresentation()
+	if M.NumGenerators() != 2 || len(M.Relations()) != 3 {
+		t.Fatalf("got %v generators and relations %v", M.NumGenerators(), M.Relations())
+	}
+	w := RawWord{{0, 2}, {1, -1}, {0, -3}}
+	if got := p.MonoidToGroupRawWord(p.GroupToMonoidRawWord(w, 2), 2); !p.EqualRawWord(got, w) {
+		t.Fatalf("round trip of %v gave %v", w, got)
+	}
+	if got := p.GroupToMonoidRawWord(w, 2); !p.EqualRawWord(got, RawWord{{0, 2}, {3, 1}, {2, 3}}) {
+		t.Fatalf("GroupToMonoidRawWord(%v, 2) = %v", w, got)
+	}
+
+	R := p.NewRewritingSystem(G, p.ShortLex{})
+	if R.Monoid == nil || len(R.LHS) != 3 {
+		t.Fatalf("expected 3 rules attached to a monoid presentation, got %v", R)
+	}
+	if got := R.Rewrite(RawWord{{0, 4}, {1, 1}, {0, 1}}); !p.EqualRawWord(got, RawWord{{0, 1}}) {
+		t.Fatalf("a^4 A a should rewrite to a, got %v", got)
+	}
+	// the rules don't depend on the random order of the relators in their map
+	rel := []p.Word{p.NewWord(RawWord{{0, 2}}), p.NewWord(RawWord{{1, 3}}), p.NewWord(RawWord{{0, 1}, {1, 1}, {0, 1}, {1, 1}}), p.NewWord(RawWord{{0, 1}, {1, -1}, {0, -1}, {1, 1}})}
+	H, err := p.NewGroupPresentation(2, p.NewWordSet(rel))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := p.NewRewritingSystem(H, p.ShortLex{})
+	for range 20 {
+		H, err := p.NewGroupPresentation(2, p.NewWordSet(rel))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if got := p.NewRewritingSystem(H, p.ShortLex{}); !slices.EqualFunc(got.LHS, want.LHS, p.EqualRawWord) {
+			t.Fatalf("got rules %v, then %v", want.LHS, got.LHS)
+		}
+	}
+}
+
+func TestPositiveBraidMonoid(t *testing.T) {
+	M, err := p.NewPositiveBraidMonoid(4)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if M.NumGenerators() != 3 || len(M.Relations()) != 3 {
+		t.Fatalf("got %v generators and relations %v", M.NumGenerators(), M.Relations())
+	}
+	R := p.NewRewritingSystem(M, p.ShortLex{})
+	// s_1 s_0 s_1 -> s_0 s_1 s_0 then s_2 s_0 -> s_0 s_2
+	if got := R.Rewrite(RawWord{{2, 1}, {1, 1}, {0, 1}, {1, 1}}); !p.EqualRawWord(got, RawWord{{0, 1}, {2, 1}, {1, 1}, {0, 1}}) {
+		t.Fatalf("got %v", got)
+	}
+	if got := R.Rewrite(RawWord{{2, 1}, {0, 1}}); !p.EqualRawWord(got, RawWord{{0, 1}, {2, 1}}) {
+		t.Fatalf("got %v", got)
+	}
+}
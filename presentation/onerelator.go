@@ -0,0 +1,564 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+)
+
+// The word problem for one-relator groups with Magnus' method, see Lyndon and Schupp, Combinatorial Group Theory, IV.5
+//
+// A Magnus subgroup of G = <X | r> is a subgroup generated by a set Y of generators omitting a generator of r.
+// By the Freiheitssatz it is free on Y, and we decide membership in Magnus subgroups, the word problem being membership in the trivial subgroup.
+// This goes by induction on the length of r:
+// - generators not in r split off as a free factor, and if r is a power of a single generator we are in a free product of a cyclic group and a free group
+// - if some generator t of r has exponent sum 0 in r, G is an HNN extension with stable letter t of a one-relator group H with a shorter relator (see hnnSplit),
+//   whose associated subgroups are Magnus subgroups of H, so Britton's lemma reduces everything to H
+// - otherwise G embeds in a one-relator group where a new generator has exponent sum 0 (see moldavanskii)
+// Words are handled as slices of letters (generator, ±1) throughout this file
+
+var (
+	ErrNotOneRelator     = errors.New("presentation: not a one-relator presentation")
+	ErrNotMagnusSubgroup = errors.New("presentation: generators of a Magnus subgroup must omit a generator of the relator")
+)
+
+// a one-relator group <0, ..., gens-1 | rel> met during the breakdown, caching its HNN splittings and embeddings
+type magnusGroup struct {
+	gens       int
+	rel        RawWord    //cyclically reduced, as letters
+	inRel      []bool     //the generators occurring in rel
+	mu         sync.Mutex //guards the caches, since G may be shared
+	splits     map[int]*hnnSplit
+	embeddings map[[2]int]*moldavanskii
+}
+
+func newMagnusGroup(gens int, rel RawWord) *magnusGroup {
+	r, _ := CyclicReduceRawWord(rel)
+	g := &magnusGroup{gens: gens, rel: expandRawWord(r), inRel: make([]bool, gens), splits: make(map[int]*hnnSplit), embeddings: make(map[[2]int]*moldavanskii)}
+	for _, x := range g.rel {
+		g.inRel[x[0]] = true
+	}
+	return g
+}
+
+// the Magnus breakdown of G, if it has a single relator, kept in G since it caches the groups met along the way
+func (G *GroupPresentation) magnusGroup() (*magnusGroup, error) {
+	G.lazy.Lock()
+	defer G.lazy.Unlock()
+	rel := G.relators()
+	if len(rel) != 1 {
+		return nil, ErrNotOneRelator
+	}
+	if G.magnus == nil {
+		for _, r := range rel {
+			G.magnus = newMagnusGroup(G.gen, r.seq)
+		}
+	}
+	return G.magnus, nil
+}
+
+// freely reduces a word given as letters
+func reduceLetters(w RawWord) RawWord {
+	return expandRawWord(ReduceRawWord(w))
+}
+
+// whether generator i is in Y, where nil is the empty set
+func inSet(Y []bool, i int) bool {
+	return Y != nil && Y[i]
+}
+
+func (g *magnusGroup) exponentSums() []int {
+	sums := make([]int, g.gens)
+	for _, x := range g.rel {
+		sums[x[0]] += x[1]
+	}
+	return sums
+}
+
+// Decides whether w is in the subgroup generated by Y, and if so writes it as a freely reduced word on Y, which is unique by the Freiheitssatz
+// Precondition: Y omits a generator of the relator, or contains all of them
+func (g *magnusGroup) member(b *budget, Y []bool, w RawWord) (RawWord, bool) {
+	w = reduceLetters(w)
+	if b.stopped(w) {
+		return nil, false
+	}
+	// G is the free product of the group on the generators of the relator and the free group on the others
+	// so we first remove the blocks which are trivial in their factor, the free blocks being freely reduced already
+	blocks := g.blocks(w)
+	for i := 0; len(blocks) > 1 && i < len(blocks); i++ {
+		if block := blocks[i]; g.inRel[block[0][0]] {
+			if _, trivial := g.memberRelator(b, nil, block); trivial {
+				w = reduceLetters(ConcatRawWord(concatLetters(blocks[:i]), concatLetters(blocks[i+1:])))
+				blocks, i = g.blocks(w), -1 //start over
+			}
+		}
+	}
+	u := make(RawWord, 0, len(w))
+	for _, block := range blocks {
+		if g.inRel[block[0][0]] {
+			v, ok := g.memberRelator(b, Y, block)
+			if !ok {
+				return nil, false
+			}
+			u = append(u, v...)
+			continue
+		}
+		for _, x := range block {
+			if !inSet(Y, x[0]) {
+				return nil, false
+			}
+		}
+		u = append(u, block...)
+	}
+	return reduceLetters(u), true
+}
+
+// splits w into maximal blocks of letters all occurring or all not occurring in the relator
+func (g *magnusGroup) blocks(w RawWord) []RawWord {
+	blocks := make([]RawWord, 0)
+	for i, x := range w {
+		if i == 0 || g.inRel[x[0]] != g.inRel[w[i-1][0]] {
+			blocks = append(blocks, RawWord{})
+		}
+		blocks[len(blocks)-1] = append(blocks[len(blocks)-1], x)
+	}
+	return blocks
+}
+
+func concatLetters(words []RawWord) RawWord {
+	w := RawWord{}
+	for _, u := range words {
+		w = append(w, u...)
+	}
+	return w
+}
+
+// member for a word on the generators of the relator, picking the stable letter of the HNN splitting
+func (g *magnusGroup) memberRelator(b *budget, Y []bool, w RawWord) (RawWord, bool) {
+	in, out := make([]int, 0), make([]int, 0) //generators of the relator in Y and not in Y
+	for i := range g.gens {
+		if g.inRel[i] && inSet(Y, i) {
+			in = append(in, i)
+		} else if g.inRel[i] {
+			out = append(out, i)
+		}
+	}
+	if len(out) == 0 {
+		return w, true //the subgroup is everything
+	} else if len(in)+len(out) == 1 { //<z | z^n>, and the subgroup is trivial
+		sum := 0
+		for _, x := range w {
+			sum += x[1]
+		}
+		return RawWord{}, sum%len(g.rel) == 0
+	}
+	sigma := g.exponentSums()
+	// a stable letter in Y keeps the subgroup generated by generators of H, see hnnSplit.memberWithStable
+	for _, t := range in {
+		if sigma[t] == 0 {
+			return g.split(t).member(b, Y, w)
+		}
+	}
+	if len(in) == 0 {
+		if s := g.zeroSum(out); s >= 0 {
+			return g.split(s).member(b, Y, w)
+		}
+		return g.embedding(out[0], out[1]).member(b, Y, w)
+	}
+	// otherwise t = in[0] has a nonzero exponent sum, and we make it the power of a new generator unless all the others have exponent sum 0
+	t := in[0]
+	for _, x := range append(append([]int{}, in[1:]...), out...) {
+		if sigma[x] != 0 {
+			return g.embedding(t, x).member(b, Y, w)
+		}
+	}
+	// then t is the only generator of the relator in Y
+	return g.split(g.zeroSum(out)).member(b, Y, w)
+}
+
+// the first generator of gens with exponent sum 0 in the relator, -1 if none
+func (g *magnusGroup) zeroSum(gens []int) int {
+	sigma := g.exponentSums()
+	for _, s := range gens {
+		if sigma[s] == 0 {
+			return s
+		}
+	}
+	return -1
+}
+
+// G as an HNN extension of H with stable letter t, where t has exponent sum 0 in the relator
+// The generators of H are x_{i,j} = t^j x_i t^-j for the generators x_i != t of the relator, with lo[i] <= j <= hi[i]
+// where lo[i] and hi[i] are the least and greatest exponent sums of t before an occurrence of x_i in the relator
+// The relator of H is the relator of G rewritten with the x_{i,j}, which is shorter since the letters t are gone
+// The associated subgroups are A, generated by the x_{i,j} with j < hi[i], and B, generated by the x_{i,j} with j > lo[i],
+// and t a t^-1 = phi(a) for a in A, where phi sends x_{i,j} to x_{i,j+1}
+// Both omit a generator of the relator of H, so they are Magnus subgroups of H
+type hnnSplit struct {
+	t      int
+	H      *magnusGroup
+	lo, hi []int
+	occurs []bool         //the generators x_i != t of the relator
+	ids    map[[2]int]int //(i, j) to the generator x_{i,j} of H
+	gen    [][2]int       //the inverse of ids
+	A, B   []bool         //generators of the associated subgroups
+}
+
+func (g *magnusGroup) split(t int) *hnnSplit {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s, ok := g.splits[t]; ok {
+		return s
+	}
+	s := &hnnSplit{t: t, lo: make([]int, g.gens), hi: make([]int, g.gens), occurs: make([]bool, g.gens), ids: make(map[[2]int]int)}
+	level := 0
+	for _, x := range g.rel {
+		if x[0] == t {
+			level += x[1]
+		} else if !s.occurs[x[0]] {
+			s.occurs[x[0]], s.lo[x[0]], s.hi[x[0]] = true, level, level
+		} else {
+			s.lo[x[0]], s.hi[x[0]] = min(s.lo[x[0]], level), max(s.hi[x[0]], level)
+		}
+	}
+	for i := range g.gens {
+		for j := s.lo[i]; s.occurs[i] && j <= s.hi[i]; j++ {
+			s.ids[[2]int{i, j}] = len(s.gen)
+			s.gen = append(s.gen, [2]int{i, j})
+		}
+	}
+	rel := make(RawWord, 0, len(g.rel))
+	level = 0
+	for _, x := range g.rel {
+		if x[0] == t {
+			level += x[1]
+		} else {
+			rel = append(rel, [2]int{s.ids[[2]int{x[0], level}], x[1]})
+		}
+	}
+	s.H = newMagnusGroup(len(s.gen), rel)
+	s.A, s.B = make([]bool, len(s.gen)), make([]bool, len(s.gen))
+	for id, x := range s.gen {
+		s.A[id], s.B[id] = x[1] < s.hi[x[0]], x[1] > s.lo[x[0]]
+	}
+	g.splits[t] = s
+	return s
+}
+
+// applies phi^d to a word on the generators of H, which must be in A (d = 1) or B (d = -1)
+func (s *hnnSplit) shift(u RawWord, d int) RawWord {
+	v := make(RawWord, len(u))
+	for k, x := range u {
+		i, j := s.gen[x[0]][0], s.gen[x[0]][1]
+		v[k] = [2]int{s.ids[[2]int{i, j + d}], x[1]}
+	}
+	return v
+}
+
+// writes a word on the generators of H with the generators of G, x_{i,j} being t^j x_i t^-j
+func (s *hnnSplit) translate(u RawWord) RawWord {
+	v := make(RawWord, 0, len(u))
+	for _, x := range u {
+		i, j := s.gen[x[0]][0], s.gen[x[0]][1]
+		v = append(v, powerLetters(s.t, j)...)
+		v = append(v, [2]int{i, x[1]})
+		v = append(v, powerLetters(s.t, -j)...)
+	}
+	return v
+}
+
+// the letters of g^e
+func powerLetters(g, e int) RawWord {
+	w := make(RawWord, abs(e))
+	for k := range w {
+		w[k] = [2]int{g, sign(e)}
+	}
+	return w
+}
+
+// Britton's lemma: writes w as h_0 t^e_1 h_1 ... t^e_n h_n with the h_k words on the generators of H and no pinch,
+// i.e. no t h t^-1 with h in A and no t^-1 h t with h in B, so that w is in H exactly when n = 0
+// Each letter x_i becomes t^-lo[i] x_{i,lo[i]} t^lo[i], and pinches are removed as soon as they appear with a stack
+func (s *hnnSplit) britton(b *budget, w RawWord) ([]RawWord, []int) {
+	if b.stopped(w) {
+		return []RawWord{{}}, nil //the result doesn't matter
+	}
+	hs, es := []RawWord{{}}, []int{}
+	pushH := func(x [2]int) {
+		top := &hs[len(hs)-1]
+		if n := len(*top); n > 0 && (*top)[n-1] == InvLetter(x) {
+			*top = (*top)[:n-1]
+		} else {
+			*top = append(*top, x)
+		}
+	}
+	pushT := func(e int) {
+		if n := len(es); n > 0 && es[n-1] == -e {
+			assoc := s.A //t h t^-1
+			if e == 1 {
+				assoc = s.B //t^-1 h t
+			}
+			if u, ok := s.H.member(b, assoc, hs[len(hs)-1]); ok {
+				hs, es = hs[:len(hs)-1], es[:n-1]
+				for _, x := range s.shift(u, -e) {
+					pushH(x)
+				}
+				return
+			}
+		}
+		hs, es = append(hs, RawWord{}), append(es, e)
+	}
+	for _, x := range w {
+		if x[0] == s.t {
+			pushT(x[1])
+			continue
+		}
+		c := s.lo[x[0]]
+		for range abs(c) {
+			pushT(-sign(c))
+		}
+		pushH([2]int{s.ids[[2]int{x[0], c}], x[1]})
+		for range abs(c) {
+			pushT(sign(c))
+		}
+	}
+	return hs, es
+}
+
+// membership in the subgroup generated by Y for a word on the generators of the relator
+func (s *hnnSplit) member(b *budget, Y []bool, w RawWord) (RawWord, bool) {
+	if inSet(Y, s.t) {
+		return s.memberWithStable(b, Y, w)
+	}
+	// the generators of Y lie in H after conjugating by t^c for a level c common to all of them
+	// (we only get here when Y has at most one generator of the relator, see memberRelator)
+	lo, hi := math.MinInt, math.MaxInt
+	for i := range s.occurs {
+		if s.occurs[i] && inSet(Y, i) {
+			lo, hi = max(lo, s.lo[i]), min(hi, s.hi[i])
+		}
+	}
+	c := 0
+	if lo > hi {
+		panic("presentation: no common level for the Magnus subgroup")
+	} else if lo > math.MinInt {
+		c = lo
+	}
+	hs, es := s.britton(b, ConcatRawWord(ConcatRawWord(powerLetters(s.t, c), w), powerLetters(s.t, -c)))
+	if len(es) > 0 {
+		return nil, false //not even in H
+	}
+	inH := make([]bool, len(s.gen))
+	for i := range s.occurs {
+		if s.occurs[i] && inSet(Y, i) {
+			inH[s.ids[[2]int{i, c}]] = true
+		}
+	}
+	u, ok := s.H.member(b, inH, hs[0])
+	if !ok {
+		return nil, false
+	}
+	v := make(RawWord, len(u))
+	for k, x := range u {
+		v[k] = [2]int{s.gen[x[0]][0], x[1]} //t^-c x_{i,c} t^c = x_i
+	}
+	return v, true
+}
+
+// Membership when t is in Y: the subgroup is generated by t and the subgroup K of H generated by the x_{i,j} with x_i in Y
+// If w is in it, w has a reduced form (no pinches) with all h_k in K, and two reduced forms only differ by sliding elements of A or B through the letters t
+// So going from left to right, h_k must be in K A or K B (depending on the next letter t), and we slide the part in A or B to the right
+// Since K and A (or B) are generated by generators of H omitting one of the relator, they generate a free group on the union of their generators,
+// so we decide membership in K A by membership in the subgroup generated by the union and then splitting the unique reduced word
+func (s *hnnSplit) memberWithStable(b *budget, Y []bool, w RawWord) (RawWord, bool) {
+	hs, es := s.britton(b, w)
+	K := make([]bool, len(s.gen))
+	for id, x := range s.gen {
+		K[id] = inSet(Y, x[0])
+	}
+	out, carry := RawWord{}, RawWord{}
+	for k, e := range es {
+		C := s.B //b t = t phi^-1(b)
+		if e == -1 {
+			C = s.A //a t^-1 = t^-1 phi(a)
+		}
+		union := make([]bool, len(s.gen))
+		for id := range union {
+			union[id] = K[id] || C[id]
+		}
+		u, ok := s.H.member(b, union, ConcatRawWord(carry, hs[k]))
+		if !ok {
+			return nil, false
+		}
+		cut := len(u) //u = u[:cut] u[cut:] with u[cut:] in C as long as possible
+		for cut > 0 && C[u[cut-1][0]] {
+			cut--
+		}
+		for _, x := range u[:cut] {
+			if !K[x[0]] {
+				return nil, false
+			}
+		}
+		out = append(append(out, s.translate(u[:cut])...), [2]int{s.t, e})
+		carry = s.shift(u[cut:], -e)
+	}
+	u, ok := s.H.member(b, K, ConcatRawWord(carry, hs[len(hs)-1]))
+	if !ok {
+		return nil, false
+	}
+	return reduceLetters(append(out, s.translate(u)...)), true
+}
+
+// The Moldavanskii embedding, for generators t and x of the relator with exponent sums alpha and beta different from 0:
+// G embeds into G* = <G, y | y^beta = t>, which is the one-relator group on the generators of G but t, and y, with relator r(t = y^beta, x = x y^-alpha)
+// (the new x being x y^alpha in G*), where y has exponent sum alpha beta - beta alpha = 0
+type moldavanskii struct {
+	t, x, y     int
+	alpha, beta int
+	split       *hnnSplit //of G* with stable letter y
+}
+
+func (g *magnusGroup) embedding(t, x int) *moldavanskii {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if m, ok := g.embeddings[[2]int{t, x}]; ok {
+		return m
+	}
+	sigma := g.exponentSums()
+	m := &moldavanskii{t: t, x: x, y: g.gens, alpha: sigma[t], beta: sigma[x]}
+	star := newMagnusGroup(g.gens+1, m.image(g.rel))
+	m.split = star.split(m.y)
+	g.embeddings[[2]int{t, x}] = m
+	return m
+}
+
+// the image of a word of G in G*
+func (m *moldavanskii) image(w RawWord) RawWord {
+	v := make(RawWord, 0, len(w))
+	for _, u := range w {
+		switch {
+		case u[0] == m.t:
+			v = append(v, powerLetters(m.y, u[1]*m.beta)...)
+		case u[0] == m.x && u[1] == 1:
+			v = append(append(v, u), powerLetters(m.y, -m.alpha)...)
+		case u[0] == m.x:
+			v = append(append(v, powerLetters(m.y, m.alpha)...), u)
+		default:
+			v = append(v, u)
+		}
+	}
+	return reduceLetters(v)
+}
+
+// The image of the subgroup generated by Y is generated by the image of Y, which are generators of G* except
+// y^beta if t is in Y and x y^-alpha if x is in Y (we only take x in Y when t is in Y, see memberRelator)
+// So we decide membership in the Magnus subgroup of G* generated by Y with y instead of t, then rewrite the result
+// in the basis where x y^-alpha replaces x, and check that the exponents of y are multiples of beta
+func (m *moldavanskii) member(b *budget, Y []bool, w RawWord) (RawWord, bool) {
+	star := make([]bool, m.y+1)
+	for i := range m.y {
+		star[i] = inSet(Y, i)
+	}
+	if star[m.t] {
+		star[m.t], star[m.y] = false, true
+	}
+	u, ok := m.split.member(b, star, m.image(w))
+	if !ok || !inSet(Y, m.t) {
+		return u, ok
+	}
+	if inSet(Y, m.x) {
+		v := make(RawWord, 0, len(u))
+		for _, x := range u {
+			switch {
+			case x[0] == m.x && x[1] == 1:
+				v = append(append(v, x), powerLetters(m.y, m.alpha)...)
+			case x[0] == m.x:
+				v = append(append(v, powerLetters(m.y, -m.alpha)...), x)
+			default:
+				v = append(v, x)
+			}
+		}
+		u = ReduceRawWord(v)
+	}
+	v := make(RawWord, 0, len(u))
+	for _, x := range ReduceRawWord(u) {
+		if x[0] != m.y {
+			v = append(v, x)
+		} else if x[1]%m.beta != 0 {
+			return nil, false
+		} else {
+			v = append(v, [2]int{m.t, x[1] / m.beta})
+		}
+	}
+	return expandRawWord(v), true
+}
+
+// IsTrivialOneRelator decides whether w is trivial in G, which must have a single relator, with Magnus' method
+// This always terminates, but the breakdown can take time exponential in the length of the relator, see IsTrivialOneRelatorContext
+func (G *GroupPresentation) IsTrivialOneRelator(w Word) (bool, error) {
+	return G.isTrivialOneRelator(w, nil)
+}
+
+// IsTrivialOneRelatorContext is IsTrivialOneRelator, stopping with ctx.Err() when ctx is done
+// or a *LimitError when a word of the breakdown has more than limits.MaxWordLength letters
+func (G *GroupPresentation) IsTrivialOneRelatorContext(ctx context.Context, w Word, limits Limits) (bool, error) {
+	return G.isTrivialOneRelator(w, newBudget(ctx, limits))
+}
+
+func (G *GroupPresentation) isTrivialOneRelator(w Word, b *budget) (bool, error) {
+	if err := G.IsValidWord(w); err != nil {
+		return false, err
+	}
+	g, err := G.magnusGroup()
+	if err != nil {
+		return false, err
+	}
+	_, ok := g.member(b, nil, expandRawWord(w.seq))
+	if b != nil && b.err != nil {
+		return false, b.err
+	}
+	return ok, nil
+}
+
+// MagnusSubgroupMember decides whether w is in the subgroup of G generated by the given generators, which must omit a generator of the single relator of G
+// If so, it also returns w written on these generators, which is unique as a freely reduced word by the Freiheitssatz
+func (G *GroupPresentation) MagnusSubgroupMember(generators []int, w Word) (Word, bool, error) {
+	return G.magnusSubgroupMember(generators, w, nil)
+}
+
+// MagnusSubgroupMemberContext is MagnusSubgroupMember, stopping like IsTrivialOneRelatorContext
+func (G *GroupPresentation) MagnusSubgroupMemberContext(ctx context.Context, generators []int, w Word, limits Limits) (Word, bool, error) {
+	return G.magnusSubgroupMember(generators, w, newBudget(ctx, limits))
+}
+
+func (G *GroupPresentation) magnusSubgroupMember(generators []int, w Word, b *budget) (Word, bool, error) {
+	if err := G.IsValidWord(w); err != nil {
+		return EmptyWord(), false, err
+	}
+	g, err := G.magnusGroup()
+	if err != nil {
+		return EmptyWord(), false, err
+	}
+	Y := make([]bool, G.gen)
+	for _, i := range generators {
+		if i < 0 || i >= G.gen {
+			return EmptyWord(), false, ErrInvalidRelation
+		}
+		Y[i] = true
+	}
+	omits := false
+	for i := range G.gen {
+		omits = omits || (g.inRel[i] && !Y[i])
+	}
+	if !omits {
+		return EmptyWord(), false, ErrNotMagnusSubgroup
+	}
+	u, ok := g.member(b, Y, expandRawWord(w.seq))
+	if b != nil && b.err != nil {
+		return EmptyWord(), false, b.err
+	} else if !ok {
+		return EmptyWord(), false, nil
+	}
+	return NewWord(ReduceRawWord(u)), true, nil
+}
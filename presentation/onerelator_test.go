@@ -0,0 +1,207 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestIsTrivialOneRelator(t *testing.T) {
+	tests := []struct {
+		name      string
+		gen       int
+		rel       RawWord
+		weights   []int //a homomorphism to Z, so words of nonzero weight are not trivial
+		automatic bool  //cross check with an automatic structure
+		trivial   []RawWord
+		nontriv   []RawWord
+	}{
+		{
+			name:      "Z^2",
+			gen:       2,
+			rel:       RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}},
+			weights:   []int{1, 1},
+			automatic: true,
+		},
+		{
+			name:    "BS(1, 2)",
+			gen:     2,
+			rel:     RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}},
+			weights: []int{0, 1},
+			trivial: []RawWord{
+				{{0, 1}, {1, 1}, {0, 1}, {1, -1}, {0, -1}, {1, 1}, {0, -1}, {1, -1}}, //[a, b a b^-1]
+				{{1, 2}, {0, 1}, {1, -2}, {0, -4}},
+				{{1, -1}, {0, 1}, {1, 1}, {0, 1}, {1, -1}, {0, -1}, {1, 1}, {0, -1}}, //[b^-1 a b, a], both in the abelian normal subgroup Z[1/2]
+			},
+			nontriv: []RawWord{
+				{{0, 1}, {1, 1}, {0, -1}, {1, -1}},
+				{{0, 2}, {1, 1}, {0, -2}, {1, -1}}, //a^-2
+			},
+		},
+		{
+			name:      "trefoil knot group",
+			gen:       2,
+			rel:       RawWord{{0, 1}, {1, 1}, {0, 1}, {1, -1}, {0, -1}, {1, -1}},
+			weights:   []int{1, 1},
+			automatic: true,
+		},
+		{
+			name:    "torus knot group <a, b | a^2 b^-3>",
+			gen:     2,
+			rel:     RawWord{{0, 2}, {1, -3}},
+			weights: []int{3, 2},
+			trivial: []RawWord{{{0, 2}, {1, 1}, {0, -2}, {1, -1}}},
+			nontriv: []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -1}}},
+		},
+		{
+			name:    "genus 2 surface group",
+			gen:     4,
+			rel:     RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}, {2, 1}, {3, 1}, {2, -1}, {3, -1}},
+			weights: []int{1, 0, 0, 1},
+			nontriv: []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -1}}, {{0, 1}, {2, 1}, {0, -1}, {2, -1}}},
+		},
+		{
+			name:    "with a free generator",
+			gen:     3,
+			rel:     RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -2}},
+			weights: []int{1, 0, 1},
+			trivial: []RawWord{{{2, 1}, {1, 1}, {2, -1}, {0, 1}, {1, 1}, {0, -1}, {1, -2}, {2, 1}, {1, -1}, {2, -1}}},
+			nontriv: []RawWord{{{2, 1}, {1, 1}, {2, -1}, {1, -1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet([]p.Word{p.NewWord(tt.rel)}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			check := func(w RawWord, want bool) {
+				t.Helper()
+				got, err := G.IsTrivialOneRelator(p.NewWord(w))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("IsTrivialOneRelator(%v) = %v, want %v", w, got, want)
+				}
+				if G.Equal(p.NewWord(w), p.EmptyWord()) != want {
+					t.Errorf("Equal(%v, 1) = %v, want %v", w, !want, want)
+				}
+			}
+			for _, w := range tt.trivial {
+				check(w, true)
+			}
+			for _, w := range tt.nontriv {
+				check(w, false)
+			}
+			rng := rand.New(rand.NewPCG(1, 2))
+			for range 30 {
+				check(productOfConjugates(rng, tt.rel, tt.gen), true)
+			}
+			var A *p.AutomaticStructure
+			if tt.automatic {
+				if A, err = G.ComputeAutomaticStructure(200); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for range 100 {
+				w := randomRawWord(rng, tt.gen, 10)
+				weight := 0
+				for _, x := range w {
+					weight += tt.weights[x[0]] * x[1]
+				}
+				if A != nil {
+					check(w, len(A.Reduce(w)) == 0)
+				} else if weight != 0 {
+					check(w, false)
+				}
+			}
+		})
+	}
+}
+
+// a product of up to 3 conjugates of r and its inverse
+func productOfConjugates(rng *rand.Rand, r RawWord, generators int) RawWord {
+	w := RawWord{}
+	for range rng.IntN(4) {
+		u, v := randomRawWord(rng, generators, 4), r
+		if rng.IntN(2) == 0 {
+			v = p.InvRawWord(r)
+		}
+		w = p.ConcatRawWord(w, p.ConjugateRawWord(v, u))
+	}
+	return w
+}
+
+func TestMagnusSubgroupMember(t *testing.T) {
+	bs := RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}} //BS(1, 2) = <a, b | b a b^-1 a^-2>
+	torus := RawWord{{0, 2}, {1, -3}}               //<a, b | a^2 b^-3>, where no generator has exponent sum 0
+	tests := []struct {
+		name       string
+		rel        RawWord
+		generators []int
+		w          RawWord
+		want       RawWord
+		ok         bool
+	}{
+		{"a^2 in <a>", bs, []int{0}, RawWord{{1, 1}, {0, 1}, {1, -1}}, RawWord{{0, 2}}, true},
+		{"b a b^-1 a in <a>", bs, []int{0}, RawWord{{1, 1}, {0, 1}, {1, -1}, {0, 1}}, RawWord{{0, 3}}, true},
+		{"b^-1 a b not in <a>", bs, []int{0}, RawWord{{1, -1}, {0, 1}, {1, 1}}, nil, false},
+		{"b not in <a>", bs, []int{0}, RawWord{{1, 1}}, nil, false},
+		{"b a^2 b^-1 in <b>", bs, []int{1}, RawWord{{1, 1}, {0, 2}, {1, -1}, {0, -4}, {1, 3}}, RawWord{{1, 3}}, true},
+		{"a not in <b>", bs, []int{1}, RawWord{{0, 1}}, nil, false},
+		{"trivial in <>", bs, nil, RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}}, RawWord{}, true},
+		{"b^3 in <a>", torus, []int{0}, RawWord{{1, 3}, {0, 1}}, RawWord{{0, 3}}, true},
+		{"b^-6 in <a>", torus, []int{0}, RawWord{{1, -6}}, RawWord{{0, -4}}, true},
+		{"b not in <a>", torus, []int{0}, RawWord{{1, 1}}, nil, false},
+		{"a^2 in <b>", torus, []int{1}, RawWord{{1, 1}, {0, 2}}, RawWord{{1, 4}}, true},
+		{"a b a^-1 not in <b>", torus, []int{1}, RawWord{{0, 1}, {1, 1}, {0, -1}}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(tt.rel)}))
+			got, ok, err := G.MagnusSubgroupMember(tt.generators, p.NewWord(tt.w))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.ok || (ok && !p.EqualWord(got, p.NewWord(tt.want))) {
+				t.Errorf("got %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(bs)}))
+	if _, _, err := G.MagnusSubgroupMember([]int{0, 1}, p.EmptyWord()); err != p.ErrNotMagnusSubgroup {
+		t.Errorf("got error %v, want ErrNotMagnusSubgroup", err)
+	}
+	H, _ := p.NewGroupPresentation(2, p.NewWordSet(nil))
+	if _, err := H.IsTrivialOneRelator(p.EmptyWord()); err != p.ErrNotOneRelator {
+		t.Errorf("got error %v, want ErrNotOneRelator", err)
+	}
+}
+
+func TestIsTrivialOneRelatorAffine(t *testing.T) {
+	// BS(1, 2) = <a, b | b a b^-1 a^-2> acts faithfully on the line by a(x) = x + 1 and b(x) = 2x
+	// so a word is trivial exactly when its affine map x -> m x + c is the identity (exact in floating point for short words)
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{1, 1}, {0, 1}, {1, -1}, {0, -2}})}))
+	rng := rand.New(rand.NewPCG(3, 4))
+	for range 300 {
+		w := randomRawWord(rng, 2, 12)
+		m, c := 1.0, 0.0
+		for _, x := range w { //compose on the right: w(x) = m (x_i(x)) + c
+			if x[0] == 0 {
+				c += m * float64(x[1])
+			} else if x[1] == 1 {
+				m *= 2
+			} else {
+				m /= 2
+			}
+		}
+		want := m == 1 && c == 0
+		if got, _ := G.IsTrivialOneRelator(p.NewWord(w)); got != want {
+			t.Errorf("IsTrivialOneRelator(%v) = %v, want %v", w, got, want)
+		}
+	}
+}
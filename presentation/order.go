@@ -0,0 +1,255 @@
+package presentation
+
+import (
+	"maps"
+	"slices"
+)
+
+// Word orderings, which decide e.g. the direction of rewriting rules and which word of a class is its normal form
+// All of them compare words letter by letter, i.e. on the expansions into (generator, ±1) letters and not on syllables
+// Words are compared as they are given, so callers usually freely reduce them first
+
+// A WordOrder is a total order on words
+// Compare returns -1 if u < v, 0 if u = v and 1 if u > v
+// The orders below are also well-orders compatible with concatenation (u < v implies xuy < xvy), which is what rewriting needs to terminate
+type WordOrder interface {
+	Compare(u, v RawWord) int
+}
+
+// reports whether u < v for the order o
+func LessRawWord(o WordOrder, u, v RawWord) bool {
+	return o.Compare(u, v) < 0
+}
+
+func LessWord(o WordOrder, u, v Word) bool {
+	return o.Compare(u.seq, v.seq) < 0
+}
+
+// Where the inverse of a generator is placed in a LetterOrder
+type InversePlacement int
+
+const (
+	InverseBefore InversePlacement = iota //a^-1 < a < b^-1 < b, as in LessLetter
+	InverseAfter                          //a < a^-1 < b < b^-1
+	InversesLast                          //a < b < a^-1 < b^-1, the inverses being in the same order as the generators
+)
+
+// An order on letters, from which the word orders below are built
+// The zero value orders generators by index with inverses before generators
+type LetterOrder struct {
+	Generators []int //generators from smallest to largest, generators not listed come after the listed ones by index
+	Inverses   InversePlacement
+}
+
+// position of generator g in the order
+func (o LetterOrder) position(g int) int {
+	for i, h := range o.Generators {
+		if h == g {
+			return i
+		}
+	}
+	return len(o.Generators) + g
+}
+
+// Compare for letters
+func (o LetterOrder) Compare(x, y [2]int) int {
+	px, py := o.position(x[0]), o.position(y[0])
+	if o.Inverses == InversesLast && x[1] != y[1] {
+		return sign(y[1] - x[1]) //the positive one is smaller
+	}
+	if px != py {
+		return sign(px - py)
+	}
+	switch {
+	case x[1] == y[1]:
+		return 0
+	case o.Inverses == InverseAfter:
+		return sign(y[1] - x[1])
+	}
+	return sign(x[1] - y[1])
+}
+
+// lexicographic comparison of letters, a proper prefix being smaller
+func (o LetterOrder) compareLex(u, v RawWord) int {
+	for i := range min(len(u), len(v)) {
+		if c := o.Compare(u[i], v[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(u) - len(v))
+}
+
+// Shortlex on letters: shorter words first, then lexicographic
+type ShortLex struct {
+	Letters LetterOrder
+}
+
+func (o ShortLex) Compare(u, v RawWord) int {
+	a, b := expandRawWord(u), expandRawWord(v)
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return o.Letters.compareLex(a, b)
+}
+
+// Weighted shortlex: words with smaller total weight first, then lexicographic
+// Letters missing from Weights have weight 1, and weights should be positive for this to be a well-order
+type WeightedShortLex struct {
+	Letters LetterOrder
+	Weights map[[2]int]int
+}
+
+func (o WeightedShortLex) weight(w RawWord) int {
+	total := 0
+	for _, u := range w {
+		weight, ok := o.Weights[[2]int{u[0], sign(u[1])}]
+		if !ok {
+			weight = 1
+		}
+		total += weight * abs(u[1])
+	}
+	return total
+}
+
+func (o WeightedShortLex) Compare(u, v RawWord) int {
+	if wu, wv := o.weight(u), o.weight(v); wu != wv {
+		return sign(wu - wv)
+	}
+	a, b := expandRawWord(u), expandRawWord(v)
+	return o.Letters.compareLex(a, b)
+}
+
+// The recursive path ordering on words, seen as terms where each letter is a unary function symbol
+// Writing u = u'x and v = v'y with x, y the last letters, the empty word is the smallest and otherwise u < v exactly when
+// - x = y and u' < v', or
+// - x < y and u' < v, or
+// - x > y and u <= v'
+// With FromLeft, the first letters are used instead of the last ones (KBMAG's rt_recursive as opposed to recursive)
+type RecursivePath struct {
+	Letters  LetterOrder
+	FromLeft bool
+}
+
+// Compare runs in O(|u||v|) time and memory by filling in the comparisons of all pairs of prefixes
+func (o RecursivePath) Compare(u, v RawWord) int {
+	a, b := expandRawWord(u), expandRawWord(v)
+	if o.FromLeft {
+		a, b = reverseSlice(a), reverseSlice(b)
+	}
+	// cmp[i][j] compares a[:i] and b[:j]
+	cmp := make([][]int8, len(a)+1)
+	for i := range cmp {
+		cmp[i] = make([]int8, len(b)+1)
+		for j := range cmp[i] {
+			switch {
+			case i == 0 && j == 0:
+				cmp[i][j] = 0
+			case i == 0:
+				cmp[i][j] = -1
+			case j == 0:
+				cmp[i][j] = 1
+			default:
+				switch o.Letters.Compare(a[i-1], b[j-1]) {
+				case 0:
+					cmp[i][j] = cmp[i-1][j-1]
+				case 1:
+					cmp[i][j] = orderSign(cmp[i][j-1] > 0)
+				default:
+					cmp[i][j] = orderSign(cmp[i-1][j] >= 0)
+				}
+			}
+		}
+	}
+	return int(cmp[len(a)][len(b)])
+}
+
+func orderSign(greater bool) int8 {
+	if greater {
+		return 1
+	}
+	return -1
+}
+
+// The wreath product ordering of Sims: every generator (and its inverse) has a level
+// To compare u and v, let k be the highest level of their letters, and write u = u_0 x_1 u_1 ... x_r u_r and v = v_0 y_1 v_1 ... y_s v_s with the x_i, y_i of level k
+// Then we compare x_1 ... x_r with y_1 ... y_s in shortlex order, and if they are equal (so r = s) we compare (u_0, ..., u_r) with (v_0, ..., v_r) lexicographically, recursively with this ordering
+// With all levels equal this is shortlex, and generators beyond Levels are at level 0
+type Wreath struct {
+	Letters LetterOrder
+	Levels  []int
+}
+
+func (o Wreath) Compare(u, v RawWord) int {
+	level := func(x [2]int) int {
+		if x[0] >= 0 && x[0] < len(o.Levels) {
+			return o.Levels[x[0]]
+		}
+		return 0
+	}
+	return compareWreath(expandRawWord(u), expandRawWord(v), o.Letters, level)
+}
+
+func compareWreath(a, b RawWord, letters LetterOrder, level func([2]int) int) int {
+	if len(a) == 0 || len(b) == 0 {
+		return sign(len(a) - len(b))
+	}
+	k := level(a[0])
+	for _, x := range a {
+		k = max(k, level(x))
+	}
+	for _, x := range b {
+		k = max(k, level(x))
+	}
+	split := func(w RawWord) (RawWord, []RawWord) {
+		top, pieces, start := RawWord{}, []RawWord{}, 0
+		for i, x := range w {
+			if level(x) == k {
+				top = append(top, x)
+				pieces = append(pieces, w[start:i])
+				start = i + 1
+			}
+		}
+		return top, append(pieces, w[start:])
+	}
+	topA, piecesA := split(a)
+	topB, piecesB := split(b)
+	if len(topA) != len(topB) {
+		return sign(len(topA) - len(topB))
+	} else if c := letters.compareLex(topA, topB); c != 0 {
+		return c
+	}
+	for i := range piecesA {
+		if c := compareWreath(piecesA[i], piecesB[i], letters, level); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// The basic wreath ordering: the wreath ordering where every letter (generators and inverses alike) has its own level, given by the letter order
+// So a word containing the largest letter more often is always bigger, no matter how long the rest is
+// This is the same order as RecursivePath (comparing last letters), but each comparison only takes O((|u|+|v|) * number of letters) time
+type BasicWreath struct {
+	Letters LetterOrder
+}
+
+func (o BasicWreath) Compare(u, v RawWord) int {
+	a, b := expandRawWord(u), expandRawWord(v)
+	// the level of a letter is its rank among the letters of u and v
+	letters := slices.SortedFunc(maps.Keys(letterSet(a, b)), o.Letters.Compare)
+	levels := make(map[[2]int]int, len(letters))
+	for i, x := range letters {
+		levels[x] = i
+	}
+	return compareWreath(a, b, o.Letters, func(x [2]int) int { return levels[x] })
+}
+
+func letterSet(words ...RawWord) map[[2]int]bool {
+	set := make(map[[2]int]bool)
+	for _, w := range words {
+		for _, x := range w {
+			set[x] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,143 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestWordOrders(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   p.WordOrder
+		smaller RawWord
+		bigger  RawWord
+	}{
+		{
+			name:    "shortlex compares letters, not syllables",
+			order:   p.ShortLex{},
+			smaller: RawWord{{0, 2}, {1, 1}},
+			bigger:  RawWord{{0, 1}, {1, 2}},
+		},
+		{
+			name:    "shortlex with inverses before",
+			order:   p.ShortLex{},
+			smaller: RawWord{{0, -1}, {1, 1}},
+			bigger:  RawWord{{0, 1}, {1, 1}},
+		},
+		{
+			name:    "shortlex with inverses after",
+			order:   p.ShortLex{Letters: p.LetterOrder{Inverses: p.InverseAfter}},
+			smaller: RawWord{{0, 1}, {1, 1}},
+			bigger:  RawWord{{0, -1}, {1, 1}},
+		},
+		{
+			name:    "shortlex with inverses last",
+			order:   p.ShortLex{Letters: p.LetterOrder{Inverses: p.InversesLast}},
+			smaller: RawWord{{1, 1}},
+			bigger:  RawWord{{0, -1}},
+		},
+		{
+			name:    "shortlex with a chosen generator order",
+			order:   p.ShortLex{Letters: p.LetterOrder{Generators: []int{2, 0, 1}}},
+			smaller: RawWord{{2, 1}, {1, 1}},
+			bigger:  RawWord{{0, 1}, {1, 1}},
+		},
+		{
+			name:    "weighted shortlex",
+			order:   p.WeightedShortLex{Weights: map[[2]int]int{{0, 1}: 3}},
+			smaller: RawWord{{1, 2}},
+			bigger:  RawWord{{0, 1}},
+		},
+		{
+			name:    "recursive: one big letter beats any number of small ones",
+			order:   p.RecursivePath{},
+			smaller: RawWord{{0, 10}},
+			bigger:  RawWord{{1, 1}},
+		},
+		{
+			name:    "recursive compares last letters",
+			order:   p.RecursivePath{},
+			smaller: RawWord{{1, 1}, {0, 1}},
+			bigger:  RawWord{{0, 1}, {1, 1}},
+		},
+		{
+			name:    "right recursive compares first letters",
+			order:   p.RecursivePath{FromLeft: true},
+			smaller: RawWord{{0, 1}, {1, 1}},
+			bigger:  RawWord{{1, 1}, {0, 1}},
+		},
+		{
+			name:    "wreath: more top level letters is bigger",
+			order:   p.Wreath{Levels: []int{0, 0, 1}},
+			smaller: RawWord{{0, 5}, {2, 1}, {1, 5}},
+			bigger:  RawWord{{2, 2}},
+		},
+		{
+			name:    "wreath: same top level letters, then the pieces in between",
+			order:   p.Wreath{Levels: []int{0, 0, 1}},
+			smaller: RawWord{{0, 1}, {2, 1}, {1, 3}},
+			bigger:  RawWord{{1, 1}, {2, 1}},
+		},
+		{
+			name:    "basic wreath",
+			order:   p.BasicWreath{},
+			smaller: RawWord{{0, 3}, {1, -1}, {0, 3}},
+			bigger:  RawWord{{1, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !p.LessRawWord(tt.order, tt.smaller, tt.bigger) || p.LessRawWord(tt.order, tt.bigger, tt.smaller) {
+				t.Fatalf("expected %v < %v", tt.smaller, tt.bigger)
+			}
+			if tt.order.Compare(tt.smaller, tt.smaller) != 0 {
+				t.Fatalf("expected %v = %v", tt.smaller, tt.smaller)
+			}
+		})
+	}
+}
+
+// every order should be total, transitive and compatible with concatenation
+func TestWordOrderAxioms(t *testing.T) {
+	orders := []p.WordOrder{
+		p.ShortLex{Letters: p.LetterOrder{Generators: []int{1, 0}, Inverses: p.InversesLast}},
+		p.WeightedShortLex{Weights: map[[2]int]int{{0, 1}: 2, {1, -1}: 3}},
+		p.RecursivePath{},
+		p.RecursivePath{FromLeft: true, Letters: p.LetterOrder{Inverses: p.InverseAfter}},
+		p.Wreath{Levels: []int{1, 0, 1}},
+		p.BasicWreath{},
+	}
+	r := rand.New(rand.NewPCG(13, 14))
+	randomWord := func() RawWord {
+		w := RawWord{}
+		for range r.IntN(5) {
+			w = append(w, [2]int{r.IntN(3), 2*r.IntN(2) - 1})
+		}
+		return w
+	}
+	for _, o := range orders {
+		for range 1000 {
+			u, v, w := randomWord(), randomWord(), randomWord()
+			if o.Compare(u, v) != -o.Compare(v, u) {
+				t.Fatalf("%#v is not antisymmetric on %v, %v", o, u, v)
+			}
+			if o.Compare(u, v) < 0 && o.Compare(v, w) < 0 && o.Compare(u, w) >= 0 {
+				t.Fatalf("%#v is not transitive on %v, %v, %v", o, u, v, w)
+			}
+			x, y := randomWord(), randomWord()
+			if c := o.Compare(u, v); c != o.Compare(p.ConcatRawWord(x, p.ConcatRawWord(u, y)), p.ConcatRawWord(x, p.ConcatRawWord(v, y))) {
+				t.Fatalf("%#v is not compatible with concatenation on %v, %v, %v, %v", o, u, v, x, y)
+			}
+		}
+	}
+	// the basic wreath order is the recursive path order
+	for range 1000 {
+		u, v := randomWord(), randomWord()
+		if (p.BasicWreath{}).Compare(u, v) != (p.RecursivePath{}).Compare(u, v) {
+			t.Fatalf("BasicWreath and RecursivePath disagree on %v, %v", u, v)
+		}
+	}
+}
@@ -3,6 +3,11 @@ package presentation
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/geometricgrouptheorydev/groups-in-go/groups"
 )
@@ -13,18 +18,44 @@ var (
 )
 
 type GroupPresentation struct {
-	gen     int            //generators
-	rel     WordSet        //set of relations with key: word.id
-	classes map[Class]bool //true means the group is in that class, false means it is not, and if a class is not a map key it means we don't know
+	gen          int                 //generators
+	rel          WordSet             //set of relations, see WordSet, each stored in the canonical form of its cyclic word up to inversion (see CyclicWord)
+	classes      map[Class]bool      //true means the group is in that class, false means it is not, and if a class is not a map key it means we don't know
+	automatic    *AutomaticStructure //set by ComputeAutomaticStructure
+	magnus       *magnusGroup        //built on demand for one-relator presentations, see onerelator.go
+	torsionRoot  Word                //r when the single relator is r^n with n >= 2, see torsion.go
+	torsionOrder int                 //n, or 0 if there is no such relator
+	newman       *Rewriter           //the rules of the Newman reduction
+	dehn         *Rewriter           //the rules of Dehn's algorithm, compiled on demand, see dehn.go
+	quotients    [][]perm            //homomorphisms to small permutation groups, found on demand, see quotient.go
+	// G is safe for concurrent use: rel, classes and automatic only change through explicit mutations (AddClass, SimplifyCyclicPresentation, ComputeAutomaticStructure...) under mu,
+	// rel being replaced rather than modified, and the data derived on demand (magnus, dehn, quotients) is computed under lazy, which is taken before mu
+	mu    sync.RWMutex
+	lazy  sync.Mutex
+	cache atomic.Pointer[reductionCache] //nil unless enabled, see cache.go
 }
 
 func TrivialPresentation() GroupPresentation {
-	return GroupPresentation{classes: trivialGroupClasses}
+	return GroupPresentation{classes: copyMap(trivialGroupClasses)}
 }
 
 // returns (a copy of) the relations of G
 func (G *GroupPresentation) Relations() WordSet {
-	return copyMap(G.rel)
+	return copyMap(G.relators())
+}
+
+// the relations of G, which are never modified in place, so they can be read without holding mu
+func (G *GroupPresentation) relators() WordSet {
+	G.mu.RLock()
+	defer G.mu.RUnlock()
+	return G.rel
+}
+
+// the relators of G sorted by their ids, for computations whose results depend on the order of the relators, since map order is random
+func (G *GroupPresentation) sortedRelators() []Word {
+	rel := slices.Collect(maps.Values(G.relators()))
+	slices.SortFunc(rel, func(u, v Word) int { return strings.Compare(u.ID(), v.ID()) }) //keys depend on the order of hash collisions
+	return rel
 }
 
 // returns the number of generators of G
@@ -34,9 +65,25 @@ func (G *GroupPresentation) NumGenerators() int {
 
 // returns (a copy of) the classes of G
 func (G *GroupPresentation) Classes() map[Class]bool {
+	G.mu.RLock()
+	defer G.mu.RUnlock()
 	return copyMap(G.classes)
 }
 
+// whether G is in the class c, and whether we know it
+func (G *GroupPresentation) class(c Class) (bool, bool) {
+	G.mu.RLock()
+	defer G.mu.RUnlock()
+	val, ok := G.classes[c]
+	return val, ok
+}
+
+// whether G is known to be in the class c
+func (G *GroupPresentation) hasClass(c Class) bool {
+	val, _ := G.class(c)
+	return val
+}
+
 // Arguments: number of generators and the relations. Invalid presentations return an error.
 func NewGroupPresentation(generators int, relations WordSet) (*GroupPresentation, error) {
 	if generators < 0 {
@@ -44,16 +91,16 @@ func NewGroupPresentation(generators int, relations WordSet) (*GroupPresentation
 	} else if len(relations) == 0 {
 		return NewFreeGroup(generators) //we'll deal with this case separately because free groups are so cool
 	}
-	reducedRelations := make(WordSet) //we reduce relations provided if possible
+	reducedRelations := make(WordSet) //we cyclically reduce relations provided and rotate them to their canonical form, see CyclicWord
 	for _, r := range relations {
 		for _, p := range r.seq {
 			if p[0] < 0 || p[0] >= generators {
 				return nil, ErrInvalidRelation
 			}
 		}
-		reduced := ReduceWord(r) //we will not add it to the relations set if empty
+		reduced := canonicalRelator(r.seq) //we will not add it to the relations set if empty
 		if CompactLen(reduced) > 0 {
-			reducedRelations.Add(ReduceWord(r))
+			reducedRelations.Add(reduced) //a relator, its cyclic permutations and its inverse all end up as the same entry
 		}
 	}
 	return initAddProperties(&GroupPresentation{gen: generators, rel: reducedRelations, classes: make(map[Class]bool)})
@@ -64,8 +111,10 @@ func NewGroupPresentation(generators int, relations WordSet) (*GroupPresentation
 func initAddProperties(G *GroupPresentation) (*GroupPresentation, error) {
 	//one-relator
 	if len(G.rel) == 1 {
-		err := G.addClasses(oneRelatorGroupClasses)
-		return G, err
+		if err := G.addClasses(oneRelatorGroupClasses); err != nil {
+			return G, err
+		}
+		return G, G.detectTorsion()
 	}
 	//cyclicity
 	if G.gen == 1 {
@@ -109,6 +158,7 @@ func (G *GroupPresentation) Id() Word {
 }
 
 // WARNING: if the word problem is not solvable for your particular presentation, false does not guarantee inequality
+// Use Decide to tell a proof of inequality from a failure to prove equality
 func (G *GroupPresentation) Equal(v Word, w Word) bool {
 	return CompactLen(G.Mu(v, G.Inv(w))) == 0 //checks if vw^-1 is the empty word
 }
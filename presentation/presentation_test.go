@@ -58,7 +58,24 @@ func TestNewGroupPresentation(t *testing.T) {
 				}),
 			wantErr: false,
 			wantRel: p.NewWordSet([]p.Word{
-				p.NewWord([][2]int{{3,2},{2,-2}}),
+				p.NewWord([][2]int{{2,2},{3,-2}}), //the canonical form of the cyclic word of c^-2d^2 up to inversion
+			}),
+			wantClasses: map[p.Class]bool{
+				p.OneRelator: true,
+			},
+		},
+		{
+			name: "rotations and inverses collapse",
+			gen: 2,
+			rel: p.NewWordSet([]p.Word{
+				p.NewWord([][2]int{{0,1},{1,1}}),
+				p.NewWord([][2]int{{1,1},{0,1}}),
+				p.NewWord([][2]int{{0,-1},{1,-1}}),
+				p.NewWord([][2]int{{1,2},{0,1},{1,-1}}), //conjugate of ba
+				}),
+			wantErr: false,
+			wantRel: p.NewWordSet([]p.Word{
+				p.NewWord([][2]int{{0,1},{1,1}}),
 			}),
 			wantClasses: map[p.Class]bool{
 				p.OneRelator: true,
@@ -81,3 +98,92 @@ func TestNewGroupPresentation(t *testing.T) {
 		})
 	}
 }
+
+
+func TestCheckCommutativityRelators(t *testing.T) {
+	tests := []struct {
+		name     string
+		gen      int
+		rel      []RawWord
+		wantAll  bool
+		wantOnly bool
+	}{
+		{
+			name:     "commutator as given",
+			gen:      2,
+			rel:      []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -1}}},
+			wantAll:  true,
+			wantOnly: true,
+		},
+		{
+			name:     "rotated inverse of the commutator",
+			gen:      2,
+			rel:      []RawWord{{{0, -1}, {1, 1}, {0, 1}, {1, -1}}},
+			wantAll:  true,
+			wantOnly: true,
+		},
+		{
+			name:     "abelian with torsion",
+			gen:      2,
+			rel:      []RawWord{{{1, 1}, {0, 1}, {1, -1}, {0, -1}}, {{1, 2}}},
+			wantAll:  true,
+			wantOnly: false,
+		},
+		{
+			name:     "missing a commutator",
+			gen:      3,
+			rel:      []RawWord{{{1, 1}, {0, 1}, {1, -1}, {0, -1}}, {{2, 1}, {0, 1}, {2, -1}, {0, -1}}},
+			wantAll:  false,
+			wantOnly: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel := make(p.WordSet)
+			for _, r := range tt.rel {
+				rel.Add(p.NewWord(r))
+			}
+			G, err := p.NewGroupPresentation(tt.gen, rel)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			all, only, err := G.CheckCommutativityRelators()
+			if err != nil || all != tt.wantAll || only != tt.wantOnly {
+				t.Fatalf("CheckCommutativityRelators() = %v, %v, %v want %v, %v", all, only, err, tt.wantAll, tt.wantOnly)
+			}
+		})
+	}
+}
+
+func TestReduceCyclic(t *testing.T) {
+	rel := make(p.WordSet)
+	rel.Add(p.NewWord(RawWord{{0, 10}}))
+	rel.Add(p.NewWord(RawWord{{0, 15}}))
+	G, err := p.NewGroupPresentation(1, rel) //Z/5
+	if err != nil || !G.Classes()[p.Cyclic] {
+		t.Fatalf("expected a cyclic presentation, got %v and error %v", G.Classes(), err)
+	}
+	tests := []struct {
+		name string
+		in   RawWord
+		want RawWord
+	}{
+		{"empty word", RawWord{}, RawWord{}},
+		{"inverse", RawWord{{0, -1}}, RawWord{{0, 4}}},
+		{"several syllables", RawWord{{0, 3}, {0, 4}, {0, -1}}, RawWord{{0, 1}}},
+		{"trivial", RawWord{{0, -10}}, RawWord{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := G.Reduce(p.NewWord(tt.in))
+			if err != nil || !p.EqualWord(got, p.NewWord(tt.want)) {
+				t.Fatalf("Reduce(%v) = %v, %v want %v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+	if len(G.Relations()) != 2 {
+		t.Errorf("Reduce changed the relations to %v", G.Relations())
+	}
+}
@@ -0,0 +1,183 @@
+package presentation
+
+import "context"
+
+// Finite quotients of a presentation, found by brute force as homomorphisms to small permutation groups
+// A homomorphism is given by the images of the generators, which must send every relator to the identity
+// If some homomorphism sends w to a nontrivial permutation, then w is nontrivial in G
+
+// we try every assignment of images to the generators as long as there are at most this many
+const maxQuotientAssignments = 1 << 15
+
+// the groups we map to: cyclic groups of order 2 to maxCyclicQuotient, then symmetric groups of degree 3 to maxSymmetricQuotient
+const (
+	maxCyclicQuotient    = 12
+	maxSymmetricQuotient = 5
+)
+
+// permutations of 0, ..., d-1, with p[i] the image of i
+type perm []int
+
+func identityPerm(d int) perm {
+	p := make(perm, d)
+	for i := range p {
+		p[i] = i
+	}
+	return p
+}
+
+// p then q, acting on the right like words are read
+func (p perm) then(q perm) perm {
+	r := make(perm, len(p))
+	for i := range p {
+		r[i] = q[p[i]]
+	}
+	return r
+}
+
+func (p perm) isIdentity() bool {
+	for i := range p {
+		if p[i] != i {
+			return false
+		}
+	}
+	return true
+}
+
+// p^e, using the order of p so huge exponents are fine
+func (p perm) power(e int) perm {
+	order, q := 1, p
+	for !q.isIdentity() {
+		q, order = q.then(p), order+1
+	}
+	e = (e%order + order) % order
+	r := identityPerm(len(p))
+	for range e {
+		r = r.then(p)
+	}
+	return r
+}
+
+// the image of w under the homomorphism sending generator i to images[i]
+func evaluatePerm(images []perm, w RawWord, d int) perm {
+	r := identityPerm(d)
+	for _, u := range w {
+		r = r.then(images[u[0]].power(u[1]))
+	}
+	return r
+}
+
+// all permutations of 0, ..., d-1, starting with the identity
+func symmetricGroup(d int) []perm {
+	if d == 0 {
+		return []perm{{}}
+	}
+	perms := make([]perm, 0)
+	for _, p := range symmetricGroup(d - 1) {
+		for i := len(p); i >= 0; i-- { //insert d-1 at position i
+			q := append(append(append(perm{}, p[:i]...), d-1), p[i:]...)
+			perms = append(perms, q)
+		}
+	}
+	return perms
+}
+
+// the rotations of 0, ..., m-1
+func cyclicGroup(m int) []perm {
+	perms := make([]perm, m)
+	for k := range perms {
+		perms[k] = make(perm, m)
+		for i := range m {
+			perms[k][i] = (i + k) % m
+		}
+	}
+	return perms
+}
+
+// the nontrivial homomorphisms from G to the groups above, as long as there aren't too many assignments to try
+// computed once and kept in G
+func (G *GroupPresentation) finiteQuotients() [][]perm {
+	G.lazy.Lock()
+	defer G.lazy.Unlock()
+	if G.quotients == nil {
+		G.quotients = make([][]perm, 0)
+		G.searchQuotients(context.Background(), func(images []perm) bool {
+			G.quotients = append(G.quotients, images)
+			return true
+		})
+	}
+	return G.quotients
+}
+
+// calls found on the nontrivial homomorphisms from G to the groups above until it returns false or ctx is done
+// this only reads G, so it can run in another goroutine
+func (G *GroupPresentation) searchQuotients(ctx context.Context, found func(images []perm) bool) {
+	targets := make([][]perm, 0)
+	for m := 2; m <= maxCyclicQuotient; m++ {
+		targets = append(targets, cyclicGroup(m))
+	}
+	for d := 3; d <= maxSymmetricQuotient; d++ {
+		targets = append(targets, symmetricGroup(d))
+	}
+	for _, elements := range targets {
+		assignments := 1
+		for range G.gen {
+			if assignments *= len(elements); assignments > maxQuotientAssignments {
+				break
+			}
+		}
+		if assignments > maxQuotientAssignments || G.gen == 0 {
+			continue
+		}
+		d := len(elements[0])
+		choice := make([]int, G.gen) //the index of the image of each generator, counting in base len(elements)
+		for range assignments {
+			if ctx.Err() != nil {
+				return
+			}
+			images := make([]perm, G.gen)
+			trivial := true
+			for i, c := range choice {
+				images[i] = elements[c]
+				trivial = trivial && c == 0 //elements[0] is the identity
+			}
+			if !trivial && G.killsRelators(images, d) && !found(images) {
+				return
+			}
+			for i := range choice {
+				if choice[i]++; choice[i] < len(elements) {
+					break
+				}
+				choice[i] = 0
+			}
+		}
+	}
+}
+
+func (G *GroupPresentation) killsRelators(images []perm, d int) bool {
+	for _, r := range G.relators() {
+		if !evaluatePerm(images, r.seq, d).isIdentity() {
+			return false
+		}
+	}
+	return true
+}
+
+// FindSeparatingQuotient looks for a homomorphism from G to a small permutation group which sends w to a nontrivial permutation, proving that w is nontrivial in G
+// It returns the images of the generators as permutations of 0, ..., d-1 (the permutation p sends i to p[i]), and false if there is none among those tried
+// These are homomorphisms to cyclic groups of order at most 12 and symmetric groups of degree at most 5, when G doesn't have too many generators for a brute force search
+func (G *GroupPresentation) FindSeparatingQuotient(w Word) ([][]int, bool) {
+	if G.IsValidWord(w) != nil {
+		return nil, false
+	}
+	for _, images := range G.finiteQuotients() {
+		if !evaluatePerm(images, w.seq, len(images[0])).isIdentity() {
+			result := make([][]int, len(images))
+			for i, p := range images {
+				result[i] = append([]int{}, p...)
+			}
+			return result, true
+		}
+	}
+	return nil, false
+}
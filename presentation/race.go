@@ -0,0 +1,143 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// A best effort decider for presentations where no complete solver applies: we race partial methods in their own goroutines
+// - Knuth-Bendix completion proves equality when the rules it found so far rewrite u v^-1 to the empty word, and both ways when it finishes
+// - Todd-Coxeter enumeration of the cosets of the trivial subgroup decides both ways when G is finite (and small enough)
+// - finite quotients prove inequality (see FindSeparatingQuotient)
+// The first conclusive answer wins and the others are cancelled
+
+// the budgets of DecideConcurrently, the racing methods starting small and doubling (or quadrupling) until an answer, cancellation or the limit
+const (
+	maxRaceRules      = 1 << 14
+	maxRaceCosets     = 1 << 22
+	maxRaceWordLength = 1 << 12
+)
+
+// how long DecideConcurrently races when ctx has no deadline
+const defaultRaceTimeout = 10 * time.Second
+
+// DecideConcurrently is DecideContext with at most 1<<14 rules, 1<<22 cosets and words of 1<<12 letters
+// Since the word problem is undecidable in general, a ctx without a deadline gets one 10 seconds from now
+func (G *GroupPresentation) DecideConcurrently(ctx context.Context, u, v Word) (Verdict, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultRaceTimeout)
+		defer cancel()
+	}
+	return G.DecideContext(ctx, u, v, Limits{MaxRules: maxRaceRules, MaxCosets: maxRaceCosets, MaxWordLength: maxRaceWordLength})
+}
+
+// DecideContext is Decide, racing the partial methods above when the solvers don't settle the question
+// Knuth-Bendix adds at most limits.MaxRules rules of length at most limits.MaxWordLength, and Todd-Coxeter defines at most limits.MaxCosets cosets
+// It returns Unknown with ctx.Err() when ctx is done first, and Unknown with the *LimitError of each method that ran out of budget when all of them give up
+// Since the word problem is undecidable in general, give ctx a deadline or set limits
+// All goroutines have stopped when it returns
+func (G *GroupPresentation) DecideContext(ctx context.Context, u, v Word, limits Limits) (Verdict, error) {
+	r, err := G.ReduceWithInfoContext(ctx, ConcatWord(u, InvWord(v)), limits)
+	if err != nil {
+		return Unknown, err
+	} else if CompactLen(r.Word) == 0 {
+		return Equal, nil
+	} else if r.Guarantee >= Complete {
+		return NotEqual, nil
+	}
+	w := r.Word
+	limits = newBudget(ctx, limits).limits
+	raceCtx, cancel := context.WithCancel(ctx)
+	methods := []func(context.Context, Word, Limits) (Verdict, error){G.raceKnuthBendix, G.raceToddCoxeter, G.raceQuotients}
+	type result struct {
+		verdict Verdict
+		err     error
+	}
+	results := make(chan result, len(methods))
+	var wg sync.WaitGroup
+	for _, m := range methods {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			verdict, err := m(raceCtx, w, limits)
+			results <- result{verdict, err}
+		}()
+	}
+	defer func() { //stop the losers
+		cancel()
+		wg.Wait()
+	}()
+	errs := make([]error, 0)
+	for range methods {
+		if r := <-results; r.verdict != Unknown {
+			return r.verdict, nil
+		} else if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return Unknown, err
+	}
+	return Unknown, errors.Join(errs...)
+}
+
+// the methods get limits where negative means no limit, and return the error that made them give up
+
+func (G *GroupPresentation) raceKnuthBendix(ctx context.Context, w Word, limits Limits) (Verdict, error) {
+	order := monoidShortLex(G.gen)
+	R := NewRewritingSystem(G, order)
+	mw := GroupToMonoidRawWord(w.seq, G.gen)
+	for rules := 32; ; rules *= 2 {
+		if limits.MaxRules >= 0 {
+			rules = min(rules, limits.MaxRules)
+		}
+		b := &budget{ctx: ctx, limits: limits}
+		b.limits.MaxRules = rules
+		S, confluent := R.knuthBendix(b, order)
+		if err := ctx.Err(); err != nil {
+			return Unknown, err
+		}
+		rw, err := S.Compile(LeftmostFirst)
+		if err != nil {
+			return Unknown, err
+		}
+		if len(rw.Rewrite(mw)) == 0 {
+			return Equal, nil //the rules are consequences of the relations
+		} else if confluent {
+			return NotEqual, nil //and irreducible words are normal forms
+		} else if !errors.Is(b.err, ErrRuleLimit) || rules == limits.MaxRules {
+			return Unknown, b.err
+		}
+	}
+}
+
+func (G *GroupPresentation) raceToddCoxeter(ctx context.Context, w Word, limits Limits) (Verdict, error) {
+	for cosets := 1 << 10; ; cosets *= 4 {
+		if limits.MaxCosets >= 0 {
+			cosets = min(cosets, limits.MaxCosets)
+		}
+		T, err := G.ToddCoxeter(ctx, nil, Limits{MaxCosets: cosets})
+		if errors.Is(err, ErrCosetLimit) && cosets != limits.MaxCosets {
+			continue
+		} else if err != nil {
+			return Unknown, err
+		} else if T.Act(0, w) == 0 {
+			return Equal, nil
+		}
+		return NotEqual, nil
+	}
+}
+
+func (G *GroupPresentation) raceQuotients(ctx context.Context, w Word, _ Limits) (Verdict, error) {
+	verdict := Unknown
+	G.searchQuotients(ctx, func(images []perm) bool {
+		if !evaluatePerm(images, w.seq, len(images[0])).isIdentity() {
+			verdict = NotEqual
+		}
+		return verdict == Unknown
+	})
+	return verdict, ctx.Err()
+}
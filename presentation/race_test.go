@@ -0,0 +1,51 @@
+package presentation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestDecideConcurrently(t *testing.T) {
+	s3 := []RawWord{{{0, 2}}, {{1, 2}}, {{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}}
+	z2z3 := []RawWord{{{0, 2}}, {{1, 3}}}
+	tests := []struct {
+		name string
+		gen  int
+		rel  []RawWord
+		u, v RawWord
+		want p.Verdict
+	}{
+		{"S3, aba = bab", 2, s3, RawWord{{0, 1}, {1, 1}, {0, 1}}, RawWord{{1, 1}, {0, 1}, {1, 1}}, p.Equal},
+		{"S3, ab != ba", 2, s3, RawWord{{0, 1}, {1, 1}}, RawWord{{1, 1}, {0, 1}}, p.NotEqual},
+		{"Z/2 * Z/3, a^2 = 1", 2, z2z3, RawWord{{0, 2}}, nil, p.Equal},
+		{"Z/2 * Z/3, (ab)^6 != 1", 2, z2z3, RawWord{{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}, nil, p.NotEqual},
+		{"trivial group in disguise", 2, []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -2}}, {{1, 1}, {0, 1}, {1, -1}, {0, -2}}}, RawWord{{0, 1}}, nil, p.Equal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet(wordsOf(tt.rel)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			got, err := G.DecideConcurrently(ctx, p.NewWord(tt.u), p.NewWord(tt.v))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DecideConcurrently(%v, %v) = %v, want %v", tt.u, tt.v, got, tt.want)
+			}
+		})
+	}
+
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet(wordsOf(z2z3)))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, err := G.DecideConcurrently(ctx, p.NewWord(RawWord{{0, 1}}), p.EmptyWord()); got != p.Unknown || err != context.Canceled {
+		t.Errorf("got %v, %v, want unknown, context.Canceled", got, err)
+	}
+}
@@ -1,57 +1,93 @@
 package presentation
 
+import "context"
+
 //In this file, we'll have the word reduction methods based on the different group presentation classes
 //reductions ordered in levels of power
 
-// the higher the priority the better the reduction algorithm for computation!
-var reduceCLassPriority = []Class{Trivial, Cyclic, FreeAbelian, Abelian, Free, OneRelator}
+// Reduce uses the first registered solver which applies to G, see solver.go
+// WARNING: unless that solver is Complete, a nonempty result does not mean that w is nontrivial, see ReduceWithInfo
+func (G *GroupPresentation) Reduce(w Word) (Word, error) {
+	r, err := G.ReduceWithInfo(w)
+	return r.Word, err
+}
 
+// ReduceWithInfo is Reduce, also telling which solver reduced w and what it guarantees
+func (G *GroupPresentation) ReduceWithInfo(w Word) (Reduction, error) {
+	return G.ReduceWithInfoContext(context.Background(), w, Limits{})
+}
 
-func (G *GroupPresentation) Reduce(w Word) (Word, error) {
-	err := G.IsValidWord(w) //we need to use the O(n) IsValidWord method because some cases panic on invalid words
+// ReduceContext is Reduce, stopping with ctx.Err() when ctx is done or a *LimitError when a word along the way has more than limits.MaxWordLength letters
+// Solvers which are not a ContextSolver only stop before they start
+func (G *GroupPresentation) ReduceContext(ctx context.Context, w Word, limits Limits) (Word, error) {
+	r, err := G.ReduceWithInfoContext(ctx, w, limits)
+	return r.Word, err
+}
+
+// ReduceWithInfoContext is ReduceWithInfo, stopping like ReduceContext
+func (G *GroupPresentation) ReduceWithInfoContext(ctx context.Context, w Word, limits Limits) (Reduction, error) {
+	s := G.solver()
+	r, err := G.reduceWith(ctx, s, w, limits)
 	if err != nil {
+		return Reduction{Word: EmptyWord()}, err
+	}
+	return Reduction{Word: r, Solver: s.Name(), Guarantee: s.Guarantee()}, nil
+}
+
+// reduces w with the solver s, which must apply to G, going through the reduction cache if there is one
+func (G *GroupPresentation) reduceWith(ctx context.Context, s WordProblemSolver, w Word, limits Limits) (Word, error) {
+	cache := G.cache.Load()
+	if cache != nil {
+		if r, ok := cache.get(w, s.Name()); ok { //only valid words are cached
+			return r, nil
+		}
+	}
+	if err := G.IsValidWord(w); err != nil { //we need to use the O(n) IsValidWord method because some cases panic on invalid words
 		return EmptyWord(), err
 	}
-	for _, c := range reduceCLassPriority {
-		if val, ok := G.classes[c]; val && ok {
-			switch c {
-			case Trivial:
-				return EmptyWord(), nil
-			case Cyclic:
-				return G.handleReduceCyclic(w), nil
-			case FreeAbelian:
-				return G.handleReduceFreeAbelian(w), nil
-			case Abelian:
-				return G.handleReduceAbelian(w), nil
-			case Free:
-				return ReduceWord(w), nil //plain old word reduction
-			case Dehn:
-				return G.DehnReduce(w), nil
-			case OneRelator:
-				return G.handleReduceOneRelator(w), nil
-			}
+	var r Word
+	if cs, ok := s.(ContextSolver); ok {
+		var err error
+		if r, err = cs.ReduceContext(ctx, G, w, limits); err != nil {
+			return EmptyWord(), err
 		}
+	} else if b := newBudget(ctx, limits); b.stopped(w.seq) {
+		return EmptyWord(), b.err
+	} else {
+		r = s.Reduce(G, w)
 	}
-	return ReduceWord(w), nil //temporary
+	if cache != nil {
+		cache.put(w, s.Name(), r)
+	}
+	return r, nil
 }
 
 // O(n)
+// normal forms are x^k with 0 <= k < order, or any x^k for the infinite cyclic group
+// The order comes from the relators rather than from SimplifyCyclicPresentation, since Reduce must not modify G (see GroupPresentation)
 func (G *GroupPresentation) handleReduceCyclic(w Word) Word {
-	G.SimplifyCyclicPresentation()
-	wordExp := ReduceRawWord(w.seq)[0][1]
-	var rel Word
-	for _, r := range G.rel {
-		rel = r //extracting the only member of G.rel
-		break
+	exp := 0
+	for _, u := range w.seq {
+		exp += u[1]
+	}
+	if order := G.cyclicOrder(); order > 0 {
+		exp = (exp%order + order) % order //Go's % keeps the sign of exp
 	}
-	relExp := rel.seq[0][1]
-	newExp := wordExp % relExp
-	if newExp < 0 {
-		newExp = -newExp
-	} else if newExp == 0 {
+	if exp == 0 {
 		return EmptyWord()
 	}
-	return NewWord(RawWord{{0, newExp}})
+	return NewWord(RawWord{{0, exp}})
+}
+
+// the order of a cyclic group on one generator, which is the gcd of the exponents of the relators, and 0 if it is infinite
+func (G *GroupPresentation) cyclicOrder() int {
+	order := 0
+	for _, r := range G.relators() {
+		for _, u := range r.seq {
+			order = GCD(order, u[1])
+		}
+	}
+	return order
 }
 
 // O(n)
@@ -63,6 +99,16 @@ func (G *GroupPresentation) handleReduceAbelian(w Word) Word {
 	return w
 }
 
+// trivial words reduce to the empty word, see IsTrivialOneRelator, and other words are only freely reduced
+// so normal forms are not unique, but Equal is right
 func (G *GroupPresentation) handleReduceOneRelator(w Word) Word {
-	return w
+	return G.reduceOneRelator(w, nil)
+}
+
+// handleReduceOneRelator, stopping when b does
+func (G *GroupPresentation) reduceOneRelator(w Word, b *budget) Word {
+	if trivial, err := G.isTrivialOneRelator(w, b); err == nil && trivial {
+		return EmptyWord()
+	}
+	return ReduceWord(w)
 }
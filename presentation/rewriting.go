@@ -1,27 +1,148 @@
 package presentation
 
+import "errors"
+
 // Replaces the first instance of sub in w by replacement
 func (w RawWord) ReplaceRawSubWordFirstMatch(sub RawWord, replacement RawWord) RawWord {
 	// Notice: we currently allow sub to be empty which effectively makes it prepend replacement on w. This may change if it causes problems.
 
-	// We expand w for now, this will change once the KMP algorithms are exponent-sensitive
-	v, s := expandRawWord(w), expandRawWord(sub)
-	index, exists := SubExpandedRawWordFirstMatch(s, v)
+	// The search works on syllables directly (see subword.go), so huge exponents are fine
+	partLeft, partRight, exists := splitAtFirstMatch(sub, w)
 	if !exists {
 		return w //nothing to change
 	}
-	// We split the slice into 3 (possibly empty parts), with the middle being replacement
-	partLeft, partRight := v[:index], v[index+len(s):]
+	// We split the word into 3 (possibly empty parts), with the middle being replacement
 	return ReduceRawWord(ConcatRawWord(partLeft, ConcatRawWord(replacement, partRight)))
 }
 
 // Treated as immutable
 type RewritingSystem struct {
-	LHS []RawWord //subwords to be replaced
-	RHS []RawWord //replacements
+	LHS    []RawWord           //subwords to be replaced
+	RHS    []RawWord           //replacements
+	Monoid *MonoidPresentation //the presentation the rules come from (see NewRewritingSystem), nil if unknown
 }
 
-// Concurrency is planned for particularly large rewriting systems
+var ErrMismatchedRules = errors.New("presentation: rewriting system has different numbers of left and right hand sides")
+
+// Rewrites w with the rules of R until no left hand side occurs in it, using the LeftmostFirst strategy
+// This compiles R every time, so use Compile when rewriting many words with the same system
+// Precondition: R has as many left hand sides as right hand sides.
+// Panics if the precondition is violated.
 func (R RewritingSystem) Rewrite(w RawWord) RawWord {
-	return w
+	rw, err := R.Compile(LeftmostFirst)
+	if err != nil {
+		panic(err)
+	}
+	return rw.Rewrite(w)
+}
+
+// Which rule to apply when several left hand sides occur in a word
+// Both strategies apply a rule at the leftmost position where some left hand side starts
+type RewriteStrategy int
+
+const (
+	LeftmostFirst   RewriteStrategy = iota //among the rules matching at the leftmost position, the one that comes first in the system
+	LeftmostLongest                        //among the rules matching at the leftmost position, the one with the longest left hand side (then the first one)
+)
+
+// A RewritingSystem compiled into an Aho-Corasick automaton (see ahocorasick.go), so that a word is scanned once for all rules at the same time
+// Treated as immutable, so it can be shared between goroutines
+type Rewriter struct {
+	automaton *acAutomaton
+	lhsLen    []int
+	rhs       [][][2]int //expanded right hand sides
+	strategy  RewriteStrategy
+}
+
+// Compiles R for rewriting with the given strategy
+// Left hand sides are freely reduced first since they are matched against freely reduced words, and empty ones are ignored
+// The automaton has one state per letter of the left hand sides, so huge exponents in them are expensive (unlike in the words being rewritten)
+func (R RewritingSystem) Compile(strategy RewriteStrategy) (*Rewriter, error) {
+	if len(R.LHS) != len(R.RHS) {
+		return nil, ErrMismatchedRules
+	}
+	patterns := make([][][2]int, len(R.LHS))
+	rw := &Rewriter{lhsLen: make([]int, len(R.LHS)), rhs: make([][][2]int, len(R.RHS)), strategy: strategy}
+	for i := range R.LHS {
+		patterns[i] = expandRawWord(ReduceRawWord(R.LHS[i]))
+		rw.lhsLen[i] = len(patterns[i])
+		rw.rhs[i] = expandRawWord(R.RHS[i])
+	}
+	rw.automaton = newACAutomaton(patterns)
+	return rw, nil
+}
+
+// whether a match of rule starting at start beats the current best one
+func (rw *Rewriter) better(start, rule, bestStart, bestRule int) bool {
+	switch {
+	case bestRule < 0 || start < bestStart:
+		return true
+	case start > bestStart:
+		return false
+	case rw.strategy == LeftmostLongest && rw.lhsLen[rule] != rw.lhsLen[bestRule]:
+		return rw.lhsLen[rule] > rw.lhsLen[bestRule]
+	}
+	return rule < bestRule
+}
+
+// Freely reduces w and rewrites it until no left hand side occurs in it, freely reducing after each rule
+// WARNING: this never returns if the system does not terminate on w, e.g. for the rule a -> a
+//
+// The word is processed letter by letter with two stacks:
+// out holds the letters already scanned (freely reduced) together with the state of the automaton after each of them,
+// and input holds the letters still to be scanned, with the next one on top.
+// Once no later match can start at or before the best match found so far, we apply it: the letters scanned after the match go back onto input,
+// then the right hand side is pushed onto input. Nothing before the match contains a left hand side, so we only rescan from there.
+func (rw *Rewriter) Rewrite(w RawWord) RawWord {
+	letters := expandRawWord(w)
+	input := make([][2]int, 0, len(letters))
+	push := func(x [2]int) { //pushes onto input, freely reducing
+		if n := len(input); n > 0 && input[n-1] == InvLetter(x) {
+			input = input[:n-1]
+		} else {
+			input = append(input, x)
+		}
+	}
+	for i := len(letters) - 1; i >= 0; i-- {
+		push(letters[i])
+	}
+	out := make([][2]int, 0, len(input))
+	states := []int{0} //states[k] is the state after reading out[:k]
+	bestStart, bestRule := -1, -1
+	for {
+		k := len(out)
+		// later matches start at k - depth or after, since the state is the longest suffix of out that can still grow into a match
+		if bestRule >= 0 && (len(input) == 0 || k-rw.automaton.states[states[k]].depth > bestStart) {
+			for i := k - 1; i >= bestStart+rw.lhsLen[bestRule]; i-- {
+				push(out[i])
+			}
+			for i := len(rw.rhs[bestRule]) - 1; i >= 0; i-- {
+				push(rw.rhs[bestRule][i])
+			}
+			out, states = out[:bestStart], states[:bestStart+1]
+			// the right hand side may cancel with what we already scanned, which contains no matches
+			for len(out) > 0 && len(input) > 0 && out[len(out)-1] == InvLetter(input[len(input)-1]) {
+				out, states = out[:len(out)-1], states[:len(states)-1]
+				input = input[:len(input)-1]
+			}
+			bestStart, bestRule = -1, -1
+			continue
+		} else if len(input) == 0 {
+			break //irreducible
+		}
+		x := input[len(input)-1]
+		input = input[:len(input)-1]
+		s := rw.automaton.step(states[k], x)
+		out, states = append(out, x), append(states, s)
+		rw.automaton.matches(s, func(rule int) {
+			if start := k + 1 - rw.lhsLen[rule]; rw.better(start, rule, bestStart, bestRule) {
+				bestStart, bestRule = start, rule
+			}
+		})
+	}
+	return syllableForm(out)
+}
+
+func (rw *Rewriter) RewriteWord(w Word) Word {
+	return NewWord(rw.Rewrite(w.seq))
 }
@@ -1,6 +1,7 @@
 package presentation_test
 
 import (
+	"math/rand/v2"
 	"testing"
 
 	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
@@ -35,6 +36,13 @@ func TestReplaceRawSubWordFirstMatch(t *testing.T) {
 			replacement: RawWord{{1,1}},
 			want: RawWord{{1,1},{4,6},{9,2},{3,-4}},
 		},
+		{
+			name: "huge exponents",
+			whole: RawWord{{0, 3000000}, {1, -2}, {2, 5000000}},
+			sub: RawWord{{0, 1000000}, {1, -2}, {2, 1}},
+			replacement: RawWord{{0, -2000000}},
+			want: RawWord{{2, 4999999}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -45,4 +53,112 @@ func TestReplaceRawSubWordFirstMatch(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		system   p.RewritingSystem
+		strategy p.RewriteStrategy
+		w        RawWord
+		want     RawWord
+	}{
+		{
+			name:   "sorting with ba -> ab",
+			system: p.RewritingSystem{LHS: []RawWord{{{1, 1}, {0, 1}}}, RHS: []RawWord{{{0, 1}, {1, 1}}}},
+			w:      RawWord{{1, 2}, {0, 3}, {1, 1}},
+			want:   RawWord{{0, 3}, {1, 3}},
+		},
+		{
+			name:     "leftmost-first",
+			system:   p.RewritingSystem{LHS: []RawWord{{{0, 1}}, {{0, 1}, {1, 1}}}, RHS: []RawWord{{{2, 1}}, {{3, 1}}}},
+			strategy: p.LeftmostFirst,
+			w:        RawWord{{0, 1}, {1, 1}},
+			want:     RawWord{{2, 1}, {1, 1}},
+		},
+		{
+			name:     "leftmost-longest",
+			system:   p.RewritingSystem{LHS: []RawWord{{{0, 1}}, {{0, 1}, {1, 1}}}, RHS: []RawWord{{{2, 1}}, {{3, 1}}}},
+			strategy: p.LeftmostLongest,
+			w:        RawWord{{0, 1}, {1, 1}},
+			want:     RawWord{{3, 1}},
+		},
+		{
+			name:     "leftmost beats first",
+			system:   p.RewritingSystem{LHS: []RawWord{{{1, 1}}, {{0, 1}, {1, 1}}}, RHS: []RawWord{{{2, 1}}, {{3, 1}}}},
+			strategy: p.LeftmostFirst,
+			w:        RawWord{{0, 1}, {1, 1}},
+			want:     RawWord{{3, 1}},
+		},
+		{
+			name: "cancellation with what was already scanned",
+			// in Z/3 x Z/3 with generators a, b: a^2 -> a^-1, b^2 -> b^-1, ba -> ab
+			system: p.RewritingSystem{
+				LHS: []RawWord{{{0, 2}}, {{1, 2}}, {{1, 1}, {0, 1}}, {{0, -2}}, {{1, -2}}, {{1, -1}, {0, 1}}, {{1, 1}, {0, -1}}, {{1, -1}, {0, -1}}},
+				RHS: []RawWord{{{0, -1}}, {{1, -1}}, {{0, 1}, {1, 1}}, {{0, 1}}, {{1, 1}}, {{0, 1}, {1, -1}}, {{0, -1}, {1, 1}}, {{0, -1}, {1, -1}}},
+			},
+			w:    RawWord{{0, 1}, {1, 1}, {0, -1}, {1, 1}, {0, 1}, {1, 1}, {0, 4}},
+			want: RawWord{{0, -1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw, err := tt.system.Compile(tt.strategy)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got := rw.Rewrite(tt.w); !p.EqualRawWord(got, tt.want) {
+				t.Fatalf("Rewrite(%v) = %v, want %v", tt.w, got, tt.want)
+			}
+		})
+	}
+	if _, err := (p.RewritingSystem{LHS: []RawWord{{{0, 1}}}}).Compile(p.LeftmostFirst); err != p.ErrMismatchedRules {
+		t.Fatalf("expected ErrMismatchedRules, got %v", err)
+	}
+}
+
+// rewriting with the automaton should agree with applying the leftmost match of any rule one at a time
+func TestRewriteAgainstNaive(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	randomWord := func(n int) RawWord {
+		w := RawWord{}
+		for range n {
+			w = append(w, [2]int{r.IntN(3), 2*r.IntN(2) - 1})
+		}
+		return p.ReduceRawWord(w)
+	}
+	naive := func(R p.RewritingSystem, w RawWord) RawWord {
+		w = p.ReduceRawWord(w)
+		for {
+			best, bestRule := -1, -1
+			for i, lhs := range R.LHS {
+				if j, ok := p.SubRawWordFirstMatch(lhs, w); ok && (bestRule < 0 || j < best) {
+					best, bestRule = j, i
+				}
+			}
+			if bestRule < 0 {
+				return w
+			}
+			w = w.ReplaceRawSubWordFirstMatch(R.LHS[bestRule], R.RHS[bestRule])
+		}
+	}
+	for range 300 {
+		var R p.RewritingSystem
+		for range r.IntN(6) + 1 {
+			lhs := randomWord(r.IntN(3) + 2)
+			if len(lhs) == 0 {
+				continue
+			}
+			n := 0
+			for _, u := range lhs {
+				n += max(u[1], -u[1])
+			}
+			R.LHS = append(R.LHS, lhs)
+			R.RHS = append(R.RHS, randomWord(r.IntN(n))) //shorter, so rewriting terminates
+		}
+		w := randomWord(20)
+		if got, want := R.Rewrite(w), naive(R, w); !p.EqualRawWord(got, want) {
+			t.Fatalf("%v.Rewrite(%v) = %v, want %v", R, w, got, want)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package presentation
+
+import (
+	"math/bits"
+	"math/rand/v2"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/internal/recompression"
+)
+
+// Straight-line programs (SLPs) represent huge words as grammar-compressed DAGs
+// Every node is either a single letter or the concatenation of two nodes, and nodes are shared, so e.g. (ab^2)^(10^9) only needs O(log 10^9) nodes
+// All operations below work on the DAG directly and never expand the word, unless explicitly asked to (RawWord, At is fine)
+// Lengths must fit in an int
+
+// This struct is treated as immutable. The zero value is the empty word.
+type SLP struct {
+	root *slpNode
+}
+
+type slpNode struct {
+	letter      [2]int //generator and exponent ±1, only used by leaves
+	left, right *slpNode
+	len         int
+	hash        uint64 //Karp-Rabin fingerprint of the word
+	pow         uint64 //slpBase^len, needed to combine fingerprints
+}
+
+// fingerprints are taken modulo the Mersenne prime 2^61-1 with a random base
+// They are only used to tell words apart quickly: different fingerprints prove the words differ, equal ones prove nothing
+const slpMod = 1<<61 - 1
+
+var slpBase = rand.Uint64N(slpMod-2) + 2
+
+func mulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	r := (lo & slpMod) + (lo >> 61) + (hi << 3) //2^61 = 1 mod p
+	r = (r & slpMod) + (r >> 61)
+	if r >= slpMod {
+		r -= slpMod
+	}
+	return r
+}
+
+func addMod(a, b uint64) uint64 {
+	r := a + b
+	if r >= slpMod {
+		r -= slpMod
+	}
+	return r
+}
+
+func powMod(b uint64, e int) uint64 {
+	r := uint64(1)
+	for e > 0 {
+		if e&1 == 1 {
+			r = mulMod(r, b)
+		}
+		b = mulMod(b, b)
+		e >>= 1
+	}
+	return r
+}
+
+// distinct codes for x and x^-1, exp = ±1
+func slpCode(gen, exp int) int {
+	return gen<<1 + (1-exp)/2
+}
+
+func slpLeaf(gen, exp int) *slpNode {
+	code := uint64(slpCode(gen, exp)+1) % slpMod
+	return &slpNode{letter: [2]int{gen, exp}, len: 1, hash: code, pow: slpBase}
+}
+
+// concatenation of two nodes, either of which may be nil (empty)
+func slpConcat(l, r *slpNode) *slpNode {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	}
+	return &slpNode{
+		left:  l,
+		right: r,
+		len:   l.len + r.len,
+		hash:  addMod(mulMod(l.hash, r.pow), r.hash),
+		pow:   mulMod(l.pow, r.pow),
+	}
+}
+
+// nth power by repeated squaring, n >= 0
+func slpPow(x *slpNode, n int) *slpNode {
+	var result *slpNode
+	for n > 0 {
+		if n&1 == 1 {
+			result = slpConcat(result, x)
+		}
+		n >>= 1
+		if n > 0 {
+			x = slpConcat(x, x)
+		}
+	}
+	return result
+}
+
+// Constructor for an SLP based on a RawWord, exponents are stored as powers so they cost O(log |exp|) nodes each
+func NewSLP(w RawWord) SLP {
+	nodes := make([]*slpNode, 0, len(w))
+	for _, u := range w {
+		if u[1] != 0 {
+			nodes = append(nodes, slpPow(slpLeaf(u[0], sign(u[1])), abs(u[1])))
+		}
+	}
+	return SLP{root: slpBalance(nodes)}
+}
+
+// concatenates nodes in a balanced way so the depth only grows logarithmically
+func slpBalance(nodes []*slpNode) *slpNode {
+	switch len(nodes) {
+	case 0:
+		return nil
+	case 1:
+		return nodes[0]
+	}
+	mid := len(nodes) / 2
+	return slpConcat(slpBalance(nodes[:mid]), slpBalance(nodes[mid:]))
+}
+
+func EmptySLP() SLP { return SLP{} }
+
+// length of the compressed word (the uncompressed length, not the size of the DAG)
+func (s SLP) Len() int {
+	if s.root == nil {
+		return 0
+	}
+	return s.root.len
+}
+
+// number of distinct nodes in the DAG, i.e. the size of the grammar
+func (s SLP) Size() int {
+	seen := make(map[*slpNode]bool)
+	var visit func(*slpNode)
+	visit = func(x *slpNode) {
+		if x == nil || seen[x] {
+			return
+		}
+		seen[x] = true
+		visit(x.left)
+		visit(x.right)
+	}
+	visit(s.root)
+	return len(seen)
+}
+
+// Returns the letter at index i as a generator exponent pair (exponent ±1) in O(depth) time
+// Careful! This function panics on an invalid index.
+func (s SLP) At(i int) [2]int {
+	if i < 0 || i >= s.Len() {
+		panic("invalid index")
+	}
+	x := s.root
+	for x.left != nil {
+		if i < x.left.len {
+			x = x.left
+		} else {
+			i -= x.left.len
+			x = x.right
+		}
+	}
+	return x.letter
+}
+
+// Returns the subword from index i to j, excluding j, as an SLP sharing its nodes with s
+// Careful! This function panics on invalid indices.
+func (s SLP) Slice(i, j int) SLP {
+	if i < 0 || j < i || j > s.Len() {
+		panic("invalid index")
+	}
+	if i == j {
+		return SLP{}
+	}
+	return SLP{root: s.root.slice(i, j)}
+}
+
+// Expands the SLP back into a RawWord (merging adjacent letters with the same generator and sign)
+// WARNING: this materializes the whole word, only use it on SLPs that are actually small
+func (s SLP) RawWord() RawWord {
+	w := RawWord{}
+	var visit func(*slpNode)
+	visit = func(x *slpNode) {
+		if x == nil {
+			return
+		}
+		if x.left == nil {
+			if n := len(w); n > 0 && w[n-1][0] == x.letter[0] && sign(w[n-1][1]) == x.letter[1] {
+				w[n-1][1] += x.letter[1]
+			} else {
+				w = append(w, x.letter)
+			}
+			return
+		}
+		visit(x.left)
+		visit(x.right)
+	}
+	visit(s.root)
+	return w
+}
+
+func (s SLP) Word() Word {
+	return NewWord(s.RawWord())
+}
+
+func ConcatSLP(a, b SLP) SLP {
+	return SLP{root: slpConcat(a.root, b.root)}
+}
+
+// nth power of an SLP, for negative n this is the -nth power of the inverse
+// O(log |n|) new nodes
+func PowSLP(n int, s SLP) SLP {
+	if n < 0 {
+		return PowSLP(-n, InvSLP(s))
+	}
+	return SLP{root: slpPow(s.root, n)}
+}
+
+// inverts an SLP, creating one new node per node of the DAG
+func InvSLP(s SLP) SLP {
+	return SLP{root: slpInv(s.root, make(map[*slpNode]*slpNode))}
+}
+
+func slpInv(x *slpNode, memo map[*slpNode]*slpNode) *slpNode {
+	if x == nil {
+		return nil
+	}
+	if inv, ok := memo[x]; ok {
+		return inv
+	}
+	var inv *slpNode
+	if x.left == nil {
+		inv = slpLeaf(x.letter[0], -x.letter[1])
+	} else {
+		inv = slpConcat(slpInv(x.right, memo), slpInv(x.left, memo)) //(uv)^-1 = v^-1u^-1
+	}
+	memo[x] = inv
+	return inv
+}
+
+// fingerprint of x[i:j] in O(depth) time
+func (x *slpNode) rangeHash(i, j int) uint64 {
+	if i >= j {
+		return 0
+	}
+	if i == 0 && j == x.len {
+		return x.hash
+	}
+	l := x.left.len
+	switch {
+	case j <= l:
+		return x.left.rangeHash(i, j)
+	case i >= l:
+		return x.right.rangeHash(i-l, j-l)
+	}
+	return addMod(mulMod(x.left.rangeHash(i, l), powMod(slpBase, j-l)), x.right.rangeHash(0, j-l))
+}
+
+// builds x[i:j] as a new node sharing as much as possible with x, O(depth) new nodes
+func (x *slpNode) slice(i, j int) *slpNode {
+	if i >= j {
+		return nil
+	}
+	if i == 0 && j == x.len {
+		return x
+	}
+	l := x.left.len
+	switch {
+	case j <= l:
+		return x.left.slice(i, j)
+	case i >= l:
+		return x.right.slice(i-l, j-l)
+	}
+	return slpConcat(x.left.slice(i, l), x.right.slice(0, j-l))
+}
+
+// compressed equality checks between nodes, with one grammar holding the rules of all the nodes seen so far
+type slpEqualizer struct {
+	g    recompression.Grammar
+	syms map[*slpNode]int
+}
+
+func newSLPEqualizer() *slpEqualizer {
+	return &slpEqualizer{syms: make(map[*slpNode]int)}
+}
+
+func (e *slpEqualizer) symbol(x *slpNode) int {
+	if s, ok := e.syms[x]; ok {
+		return s
+	}
+	var s int
+	if x.left == nil {
+		s = e.g.Run(slpCode(x.letter[0], x.letter[1]), 1)
+	} else {
+		s = e.g.Concat(e.symbol(x.left), e.symbol(x.right))
+	}
+	e.syms[x] = s
+	return s
+}
+
+// whether the nonempty nodes x and y derive the same word
+func (e *slpEqualizer) equal(x, y *slpNode) bool {
+	if x.len != y.len || x.hash != y.hash {
+		return false
+	}
+	return e.g.Equal(e.symbol(x), e.symbol(y))
+}
+
+// EqualSLP checks whether two SLPs represent the same word letter for letter (not in the group!)
+// This is the compressed equality problem, which we solve deterministically with Jeż's recompression (see internal/recompression)
+// in time polynomial in the size of the DAGs. Fingerprints rule out most unequal pairs before that
+func EqualSLP(a, b SLP) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	return a.Len() == 0 || newSLPEqualizer().equal(a.root, b.root)
+}
+
+// Free reduction of an SLP, without expanding it
+// Bottom up, if X = YZ with Y, Z already reduced, the cancellation between Y and Z is the longest suffix of Y that is the inverse of a prefix of Z
+// Since Y and Z are reduced, a suffix of length k cancels exactly when all shorter ones do, so we binary search k with compressed equality checks
+// This runs in time polynomial in the size of the DAG (in the spirit of Lohrey's solution to the compressed word problem)
+func ReduceSLP(s SLP) SLP {
+	reduced := make(map[*slpNode]*slpNode)
+	inverses := make(map[*slpNode]*slpNode)
+	eq := newSLPEqualizer()
+	var reduce func(*slpNode) *slpNode
+	reduce = func(x *slpNode) *slpNode {
+		if x == nil || x.left == nil {
+			return x
+		}
+		if r, ok := reduced[x]; ok {
+			return r
+		}
+		y, z := reduce(x.left), reduce(x.right)
+		var r *slpNode
+		if y == nil || z == nil {
+			r = slpConcat(y, z)
+		} else {
+			zInv := slpInv(z, inverses)
+			// the inverse of the prefix of z of length k is the suffix of zInv of length k
+			lo, hi := 0, min(y.len, z.len)
+			for lo < hi {
+				k := (lo + hi + 1) / 2
+				if y.rangeHash(y.len-k, y.len) == zInv.rangeHash(z.len-k, z.len) && eq.equal(y.slice(y.len-k, y.len), zInv.slice(z.len-k, z.len)) {
+					lo = k
+				} else {
+					hi = k - 1
+				}
+			}
+			r = slpConcat(y.slice(0, y.len-lo), z.slice(lo, z.len))
+		}
+		reduced[x] = r
+		return r
+	}
+	return SLP{root: reduce(s.root)}
+}
+
+// Solves the compressed word problem for free groups: checks whether a = b in the free group on their generators
+// Like ReduceSLP, this is deterministic
+func FreeEqualSLP(a, b SLP) bool {
+	return ReduceSLP(ConcatSLP(a, InvSLP(b))).Len() == 0
+}
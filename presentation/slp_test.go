@@ -0,0 +1,101 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestReduceSLP(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		// short random words with lots of cancellation, compared against ReduceRawWord
+		w := RawWord{}
+		for range r.IntN(12) {
+			w = append(w, [2]int{r.IntN(2), r.IntN(5) - 2})
+		}
+		s := p.ConcatSLP(p.NewSLP(w), p.InvSLP(p.NewSLP(w[:len(w)/2])))
+		want := p.ReduceRawWord(p.ConcatRawWord(w, p.InvRawWord(w[:len(w)/2])))
+		got := p.ReduceSLP(s).RawWord()
+		if !p.EqualRawWord(got, want) {
+			t.Fatalf("ReduceSLP(%v) = %v want %v", s.RawWord(), got, want)
+		}
+	}
+}
+
+func TestFreeEqualSLP(t *testing.T) {
+	abb := p.NewSLP(RawWord{{0, 1}, {1, 2}})
+	huge := p.PowSLP(1000000000, abb)
+	tests := []struct {
+		name string
+		u    p.SLP
+		v    p.SLP
+		want bool
+	}{
+		{
+			name: "huge power against its split",
+			u:    huge,
+			v:    p.ConcatSLP(p.PowSLP(999999999, abb), abb),
+			want: true,
+		},
+		{
+			name: "huge power against a shifted power",
+			u:    huge,
+			v:    p.ConcatSLP(p.PowSLP(999999999, p.NewSLP(RawWord{{1, 2}, {0, 1}})), abb),
+			want: false,
+		},
+		{
+			name: "conjugate of a huge power",
+			u:    p.ConcatSLP(p.ConcatSLP(p.NewSLP(RawWord{{0, -1}}), huge), p.NewSLP(RawWord{{0, 1}})),
+			v:    p.PowSLP(1000000000, p.NewSLP(RawWord{{1, 2}, {0, 1}})),
+			want: true,
+		},
+		{
+			name: "inverse is not equal",
+			u:    huge,
+			v:    p.PowSLP(-1000000000, abb),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.FreeEqualSLP(tt.u, tt.v); got != tt.want {
+				t.Fatalf("FreeEqualSLP = %v want %v", got, tt.want)
+			}
+		})
+	}
+	if huge.Len() != 3000000000 {
+		t.Fatalf("huge.Len() = %v want 3000000000", huge.Len())
+	}
+	if huge.At(4) != [2]int{1, 1} {
+		t.Fatalf("huge.At(4) = %v want [1 1]", huge.At(4))
+	}
+}
+
+func TestEqualSLP(t *testing.T) {
+	ab, ba := p.NewSLP(RawWord{{0, 1}, {1, 1}}), p.NewSLP(RawWord{{1, 1}, {0, 1}})
+	a, b := p.NewSLP(RawWord{{0, 1}}), p.NewSLP(RawWord{{1, 1}})
+	const n = 100000000
+	tests := []struct {
+		name string
+		u    p.SLP
+		v    p.SLP
+		want bool
+	}{
+		{"bracketed differently", p.PowSLP(n, ab), p.ConcatSLP(p.ConcatSLP(a, p.PowSLP(n-1, ba)), b), true},
+		{"shifted", p.ConcatSLP(a, p.PowSLP(n, ba)), p.ConcatSLP(p.PowSLP(n, ab), b), false},
+		{"not freely reduced", p.ConcatSLP(p.PowSLP(n, ab), p.InvSLP(b)), p.ConcatSLP(p.PowSLP(n-1, ab), p.NewSLP(RawWord{{0, 1}, {1, 1}, {1, -1}})), true},
+		{"equal in the group only", p.ConcatSLP(ab, p.InvSLP(ab)), p.EmptySLP(), false},
+		{"empty", p.EmptySLP(), p.NewSLP(RawWord{}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.EqualSLP(tt.u, tt.v); got != tt.want {
+				t.Fatalf("EqualSLP = %v want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package presentation
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Reduce tries the registered word problem solvers in order of decreasing priority and uses the first one that applies to the presentation
+// The built in solvers, registered below, use the classes of the presentation
+// Other packages can add their own with RegisterSolver, e.g. from the init function of the package building the structure the solver needs
+
+// What a solver promises about Reduce(G, w), each guarantee including the previous ones
+type Guarantee int
+
+const (
+	Sound       Guarantee = iota //the result is equal to w in G, so an empty result means that w is trivial
+	Complete                     //trivial words reduce to the empty word, so this solves the word problem
+	NormalForms                  //equal words reduce to the same word
+)
+
+func (g Guarantee) String() string {
+	switch g {
+	case Sound:
+		return "sound"
+	case Complete:
+		return "complete"
+	case NormalForms:
+		return "normal forms"
+	}
+	return "unknown"
+}
+
+type WordProblemSolver interface {
+	Name() string
+	Applies(G *GroupPresentation) bool
+	// Precondition: Applies(G) and w uses only generators of G (Reduce checks both)
+	Reduce(G *GroupPresentation, w Word) Word
+	Guarantee() Guarantee
+}
+
+// A solver whose reductions can stop early, see ReduceContext
+type ContextSolver interface {
+	WordProblemSolver
+	// Reduce, returning ctx.Err() when ctx is done and a *LimitError when it runs out of a budget of limits
+	ReduceContext(ctx context.Context, G *GroupPresentation, w Word, limits Limits) (Word, error)
+}
+
+// The result of Reduce together with the solver that produced it
+type Reduction struct {
+	Word      Word
+	Solver    string //name of the solver
+	Guarantee Guarantee
+}
+
+type registeredSolver struct {
+	solver   WordProblemSolver
+	priority int
+}
+
+var (
+	solversMu sync.RWMutex
+	solvers   []registeredSolver //by decreasing priority, then in the order they were registered
+)
+
+// RegisterSolver adds s to the solvers tried by Reduce, before those with a lower priority and after those with the same or a higher one
+// The built in solvers have the priorities below, and free reduction (priority 0) applies to every presentation
+// So a solver with a negative priority is never used by Reduce
+func RegisterSolver(s WordProblemSolver, priority int) {
+	solversMu.Lock()
+	defer solversMu.Unlock()
+	i := 0
+	for i < len(solvers) && solvers[i].priority >= priority {
+		i++
+	}
+	solvers = slices.Insert(solvers, i, registeredSolver{solver: s, priority: priority})
+}
+
+// Solvers returns the registered solvers in the order Reduce tries them
+func Solvers() []WordProblemSolver {
+	solversMu.RLock()
+	defer solversMu.RUnlock()
+	l := make([]WordProblemSolver, len(solvers))
+	for i, s := range solvers {
+		l[i] = s.solver
+	}
+	return l
+}
+
+// the first registered solver which applies to G
+func (G *GroupPresentation) solver() WordProblemSolver {
+	solversMu.RLock()
+	defer solversMu.RUnlock()
+	for _, s := range solvers {
+		if s.solver.Applies(G) {
+			return s.solver
+		}
+	}
+	return nil //unreachable as long as free reduction is registered
+}
+
+// a built in solver
+type funcSolver struct {
+	name      string
+	guarantee Guarantee
+	applies   func(G *GroupPresentation) bool
+	reduce    func(G *GroupPresentation, w Word) Word
+	// reduce stopping when b does, nil if the solver only checks b before it starts
+	reduceBudget func(G *GroupPresentation, w Word, b *budget) Word
+}
+
+func (s funcSolver) Name() string                             { return s.name }
+func (s funcSolver) Applies(G *GroupPresentation) bool        { return s.applies(G) }
+func (s funcSolver) Reduce(G *GroupPresentation, w Word) Word { return s.reduce(G, w) }
+func (s funcSolver) Guarantee() Guarantee                     { return s.guarantee }
+
+func (s funcSolver) ReduceContext(ctx context.Context, G *GroupPresentation, w Word, limits Limits) (Word, error) {
+	b := newBudget(ctx, limits)
+	if b.stopped(w.seq) {
+		return EmptyWord(), b.err
+	} else if s.reduceBudget == nil {
+		return s.reduce(G, w), nil
+	}
+	r := s.reduceBudget(G, w, b)
+	if b.err != nil {
+		return EmptyWord(), b.err
+	}
+	return r, nil
+}
+
+// a built in solver for the presentations in class c
+func classSolver(c Class, guarantee Guarantee, reduce func(G *GroupPresentation, w Word) Word) funcSolver {
+	return funcSolver{name: string(c), guarantee: guarantee, applies: func(G *GroupPresentation) bool { return G.hasClass(c) }, reduce: reduce}
+}
+
+// the built in solvers, the higher the priority the better the reduction algorithm for computation!
+func init() {
+	RegisterSolver(classSolver(Trivial, NormalForms, func(*GroupPresentation, Word) Word { return EmptyWord() }), 1000)
+	RegisterSolver(classSolver(Cyclic, NormalForms, (*GroupPresentation).handleReduceCyclic), 900)
+	RegisterSolver(classSolver(FreeAbelian, NormalForms, (*GroupPresentation).handleReduceFreeAbelian), 800)
+	automatic := classSolver(Automatic, NormalForms, func(G *GroupPresentation, w Word) Word { return G.AutomaticStructure().ReduceWord(w) })
+	automatic.applies = func(G *GroupPresentation) bool { return G.hasClass(Automatic) && G.AutomaticStructure() != nil } //the class may have been added by hand
+	RegisterSolver(automatic, 700)
+	RegisterSolver(classSolver(Abelian, Sound, (*GroupPresentation).handleReduceAbelian), 600)
+	RegisterSolver(classSolver(Free, NormalForms, func(_ *GroupPresentation, w Word) Word { return ReduceWord(w) }), 500)
+	RegisterSolver(classSolver(Dehn, Complete, (*GroupPresentation).DehnReduce), 400)
+	torsion := classSolver(OneRelatorTorsion, Complete, (*GroupPresentation).NewmanReduce)
+	torsion.applies = func(G *GroupPresentation) bool { return G.hasClass(OneRelatorTorsion) && G.newman != nil } //the class may have been added by hand
+	RegisterSolver(torsion, 300)
+	oneRelator := classSolver(OneRelator, Complete, (*GroupPresentation).handleReduceOneRelator)
+	oneRelator.reduceBudget = (*GroupPresentation).reduceOneRelator
+	RegisterSolver(oneRelator, 200)
+	RegisterSolver(funcSolver{name: "free reduction", guarantee: Sound, applies: func(*GroupPresentation) bool { return true }, reduce: func(_ *GroupPresentation, w Word) Word { return ReduceWord(w) }}, 0)
+}
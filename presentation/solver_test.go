@@ -0,0 +1,86 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestReduceWithInfo(t *testing.T) {
+	commutator := RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}}
+	surface := RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}, {2, 1}, {3, 1}, {2, -1}, {3, -1}}
+	tests := []struct {
+		name      string
+		gen       int
+		rel       []RawWord
+		dehn      bool //add the Dehn class by hand
+		solver    string
+		guarantee p.Guarantee
+	}{
+		{"free group", 2, nil, false, "free", p.NormalForms},
+		{"Z/5", 1, []RawWord{{{0, 5}}, {{0, 10}}}, false, "cyclic", p.NormalForms},
+		{"one relator", 2, []RawWord{{{1, 1}, {0, 1}, {1, -1}, {0, -2}}}, false, "one_relator", p.Complete},
+		{"one relator with torsion", 2, []RawWord{{{0, 1}, {1, 1}, {0, 1}, {1, 1}}}, false, "one_relator_torsion", p.Complete},
+		{"genus 2 surface group", 4, []RawWord{surface}, true, "dehn", p.Complete},
+		{"two relators", 2, []RawWord{commutator, {{0, 2}, {1, 3}}}, false, "free reduction", p.Sound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet(wordsOf(tt.rel)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.dehn {
+				G.AddClass(p.Dehn, true)
+			}
+			r, err := G.ReduceWithInfo(p.NewWord(RawWord{{0, 1}, {0, -1}}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Solver != tt.solver || r.Guarantee != tt.guarantee || p.CompactLen(r.Word) != 0 {
+				t.Errorf("got %v by %v (%v), want the empty word by %v (%v)", r.Word, r.Solver, r.Guarantee, tt.solver, tt.guarantee)
+			}
+			if r.Guarantee < p.Complete {
+				return
+			}
+			rng := rand.New(rand.NewPCG(7, 8))
+			for _, rel := range tt.rel {
+				for range 20 {
+					w := p.NewWord(productOfConjugates(rng, rel, tt.gen))
+					if got, _ := G.Reduce(w); p.CompactLen(got) != 0 {
+						t.Errorf("Reduce(%v) = %v, want the empty word", w, got)
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := (&GroupPresentation{}).ReduceWithInfo(p.NewWord(RawWord{{0, 1}})); err == nil {
+		t.Errorf("no error for an invalid word")
+	}
+}
+
+// reduces every word to the empty word in presentations on 7 generators, which no other test uses
+type sevenSolver struct{}
+
+func (sevenSolver) Name() string                               { return "seven" }
+func (sevenSolver) Applies(G *p.GroupPresentation) bool        { return G.NumGenerators() == 7 }
+func (sevenSolver) Reduce(*p.GroupPresentation, p.Word) p.Word { return p.EmptyWord() }
+func (sevenSolver) Guarantee() p.Guarantee                     { return p.Sound }
+
+func TestRegisterSolver(t *testing.T) {
+	p.RegisterSolver(sevenSolver{}, 2000)
+	G, _ := p.NewGroupPresentation(7, p.NewWordSet(nil))
+	r, _ := G.ReduceWithInfo(p.NewWord(RawWord{{6, 3}}))
+	if r.Solver != "seven" || p.CompactLen(r.Word) != 0 {
+		t.Errorf("got %v by %v, want the empty word by seven", r.Word, r.Solver)
+	}
+	if s := p.Solvers(); s[0].Name() != "seven" || s[len(s)-1].Name() != "free reduction" {
+		t.Errorf("solvers in the wrong order")
+	}
+	H, _ := p.NewGroupPresentation(6, p.NewWordSet(nil))
+	if r, _ := H.ReduceWithInfo(p.NewWord(RawWord{{5, 3}})); r.Solver != "free" {
+		t.Errorf("got solver %v, want free", r.Solver)
+	}
+}
@@ -0,0 +1,195 @@
+package presentation
+
+// Subword search directly on the (generator, exponent) syllables of RawWords, without expanding them
+// A pattern sub = x_1^e_1 ... x_m^e_m occurs in whole exactly when
+// - the inner syllables x_2^e_2 ... x_{m-1}^e_{m-1} occur as consecutive syllables of whole, exactly
+// - the syllable right before them has generator x_1, the same sign as e_1 and at least |e_1| letters
+// - the syllable right after them has generator x_m, the same sign as e_m and at least |e_m| letters
+// So we run KMP on the syllables and check the two ends, which takes time proportional to the number of syllables
+
+// merges adjacent syllables with the same generator and sign and drops 0 exponents
+// unlike ReduceRawWord this never cancels anything, so the letters of the word are unchanged
+func syllableForm(w RawWord) RawWord {
+	s := make(RawWord, 0, len(w))
+	for _, u := range w {
+		if u[1] == 0 {
+			continue
+		}
+		if n := len(s); n > 0 && s[n-1][0] == u[0] && sign(s[n-1][1]) == sign(u[1]) {
+			s[n-1][1] += u[1]
+		} else {
+			s = append(s, u)
+		}
+	}
+	return s
+}
+
+// checks whether the syllable t contains s at its end/start, i.e. same generator, same sign and at least as long
+func syllableContains(t, s [2]int) bool {
+	return t[0] == s[0] && sign(t[1]) == sign(s[1]) && abs(t[1]) >= abs(s[1])
+}
+
+// index of the syllable of whole (in syllable form) where the first match of sub (in syllable form) starts
+// the match starts abs(sub[0][1]) letters before the end of that syllable, except for one-syllable patterns where it starts at the beginning
+func firstSyllableMatch(sub, whole RawWord) (int, bool) {
+	m := len(sub)
+	switch m {
+	case 0:
+		return 0, true
+	case 1:
+		for i, t := range whole {
+			if syllableContains(t, sub[0]) {
+				return i, true
+			}
+		}
+		return -1, false
+	}
+	inner := sub[1 : m-1]
+	if len(inner) == 0 { //two syllables, no need for KMP
+		for i := 0; i+1 < len(whole); i++ {
+			if syllableContains(whole[i], sub[0]) && syllableContains(whole[i+1], sub[1]) {
+				return i, true
+			}
+		}
+		return -1, false
+	}
+	for _, j := range KMPSearchSub(inner, whole) {
+		i, k := j-1, j+len(inner)
+		if i >= 0 && k < len(whole) && syllableContains(whole[i], sub[0]) && syllableContains(whole[k], sub[m-1]) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Returns the (expanded) index of the start of the first match of sub in whole and true if there is a match
+// Otherwise, return -1 and false (-1 is used as that is not a valid index in Go, always check the boolean to avoid panics)
+// Runs in time proportional to the number of syllables, so exponents can be as large as we want
+// For example SubRawWordFirstMatch({{1,1},{3,2}}, {{2,7},{1,3},{3,4}}) returns 9, true
+func SubRawWordFirstMatch(sub, whole RawWord) (int, bool) {
+	s, w := syllableForm(sub), syllableForm(whole)
+	i, ok := firstSyllableMatch(s, w)
+	if !ok {
+		return -1, false
+	}
+	index := 0
+	for _, u := range w[:i] {
+		index += abs(u[1])
+	}
+	if len(s) > 1 {
+		index += abs(w[i][1]) - abs(s[0][1])
+	}
+	return index, true
+}
+
+// splits whole around the first match of sub, so that whole = left sub right letter for letter
+func splitAtFirstMatch(sub, whole RawWord) (RawWord, RawWord, bool) {
+	s, w := syllableForm(sub), syllableForm(whole)
+	i, ok := firstSyllableMatch(s, w)
+	if !ok {
+		return nil, nil, false
+	}
+	left := append(RawWord{}, w[:i]...)
+	var right RawWord
+	switch len(s) {
+	case 0:
+		right = append(right, w...)
+	case 1:
+		if rest := w[i][1] - s[0][1]; rest != 0 { //same sign so this only shortens the syllable
+			right = append(right, [2]int{w[i][0], rest})
+		}
+		right = append(right, w[i+1:]...)
+	default:
+		if rest := w[i][1] - s[0][1]; rest != 0 {
+			left = append(left, [2]int{w[i][0], rest})
+		}
+		k := i + len(s) - 1
+		if rest := w[k][1] - s[len(s)-1][1]; rest != 0 {
+			right = append(right, [2]int{w[k][0], rest})
+		}
+		right = append(right, w[k+1:]...)
+	}
+	return left, right, true
+}
+
+// Cyclic subword search
+// Reading w cyclically, sub occurs at index i of w when sub is a prefix of the cyclic permutation of w starting at i (possibly wrapping around, even several times)
+// We run KMPSearchSubAt on the virtual word w w w ... of length len(w) + len(sub) - 1, whose letters are w.At(i % len(w)), so nothing is copied
+// The inverse of w is read the same way through its letters inv(w.At(len(w) - 1 - i))
+// Dehn's algorithm, pieces of small cancellation theory and the conjugacy problem all need this for relators, which are cyclically reduced (see CyclicWord)
+
+// Returns every index i in [0, w.Len()) such that sub occurs at i in w read cyclically, in increasing order
+// sub and w may be different Indexable types as long as their letters are of the same type
+func CyclicSearchSubIdx[T comparable, S Indexable[T, S], R Indexable[T, R]](sub S, w R) []int {
+	return cyclicSearch(sub.At, w.At, sub.Len(), w.Len())
+}
+
+func cyclicSearch[T comparable](subAt, wAt func(int) T, lenSub, lenW int) []int {
+	if lenW == 0 {
+		return []int{} //no positions at all
+	} else if lenSub == 0 {
+		all := make([]int, lenW)
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+	doubledAt := func(i int) T { return wAt(i % lenW) }
+	return KMPSearchSubAt(subAt, doubledAt, lenSub, lenW+lenSub-1)
+}
+
+func CyclicSearchSubWord(sub, w Word) []int {
+	return CyclicSearchSubIdx(sub, w)
+}
+
+func CyclicSearchSubRawWord(sub, w RawWord) []int {
+	return CyclicSearchSubWord(NewWord(syllableForm(sub)), NewWord(syllableForm(w)))
+}
+
+// A match of a pattern in a cyclic word or its inverse
+// Index is the position where the match starts in w read cyclically, or in the inverse of w read cyclically if Inverse is true
+type CyclicMatch struct {
+	Index   int
+	Inverse bool
+}
+
+// Returns every match of sub in w and in the inverse of w, both read cyclically, see CyclicSearchSubIdx
+// Matches in w come first, then matches in its inverse, each in increasing order
+// inv inverts a letter, e.g. InvLetter
+func CyclicInvSearchSubIdx[T comparable, S Indexable[T, S], R Indexable[T, R]](sub S, w R, inv func(T) T) []CyclicMatch {
+	n := w.Len()
+	matches := make([]CyclicMatch, 0)
+	for _, i := range cyclicSearch(sub.At, w.At, sub.Len(), n) {
+		matches = append(matches, CyclicMatch{Index: i})
+	}
+	invAt := func(i int) T { return inv(w.At(n - 1 - i)) }
+	for _, i := range cyclicSearch(sub.At, invAt, sub.Len(), n) {
+		matches = append(matches, CyclicMatch{Index: i, Inverse: true})
+	}
+	return matches
+}
+
+func CyclicInvSearchSubWord(sub, w Word) []CyclicMatch {
+	return CyclicInvSearchSubIdx(sub, w, InvLetter)
+}
+
+func CyclicInvSearchSubRawWord(sub, w RawWord) []CyclicMatch {
+	return CyclicInvSearchSubWord(NewWord(syllableForm(sub)), NewWord(syllableForm(w)))
+}
+
+// Checks whether sub is a subword of some cyclic permutation of w, or of w^-1 if withInverse is true
+// Unlike the search functions above, this stops at the first match
+func IsCyclicSubWord(sub, w Word, withInverse bool) bool {
+	n := w.Len()
+	if n == 0 {
+		return sub.Len() == 0
+	}
+	length := n + sub.Len() - 1
+	doubledAt := func(i int) [2]int { return w.At(i % n) }
+	if _, ok := KMPSubFirstMatchAt(sub.At, doubledAt, sub.Len(), length); ok || !withInverse {
+		return ok
+	}
+	invAt := func(i int) [2]int { return InvLetter(w.At(n - 1 - i%n)) }
+	_, ok := KMPSubFirstMatchAt(sub.At, invAt, sub.Len(), length)
+	return ok
+}
@@ -0,0 +1,178 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+// the syllable based search should agree with KMP on the expanded words
+func TestSubRawWordFirstMatchAgainstKMP(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	expand := func(w RawWord) RawWord {
+		e := RawWord{}
+		for _, u := range w {
+			for range max(u[1], -u[1]) {
+				e = append(e, [2]int{u[0], max(min(u[1], 1), -1)})
+			}
+		}
+		return e
+	}
+	randomWord := func(n int) RawWord {
+		w := RawWord{}
+		for range r.IntN(n) {
+			w = append(w, [2]int{r.IntN(2), r.IntN(7) - 3})
+		}
+		return w
+	}
+	for range 2000 {
+		sub, whole := randomWord(4), randomWord(12)
+		gotInt, gotBool := p.SubRawWordFirstMatch(sub, whole)
+		wantInt, wantBool := p.SubExpandedRawWordFirstMatch(expand(sub), expand(whole))
+		if gotInt != wantInt || gotBool != wantBool {
+			t.Fatalf("SubRawWordFirstMatch(%v, %v) = %v, %v, want %v, %v", sub, whole, gotInt, gotBool, wantInt, wantBool)
+		}
+	}
+}
+
+func TestSubRawWordFirstMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		sub      RawWord
+		whole    RawWord
+		wantInt  int
+		wantBool bool
+	}{
+		{
+			name:     "doc example",
+			sub:      RawWord{{1, 1}, {3, 2}},
+			whole:    RawWord{{2, 7}, {1, 3}, {3, 4}},
+			wantInt:  9,
+			wantBool: true,
+		},
+		{
+			name:     "huge exponents",
+			sub:      RawWord{{0, 999999}, {1, -3}, {2, 1000000}},
+			whole:    RawWord{{2, 5}, {0, 1000000}, {1, -3}, {2, 1000000}, {0, 1000000}, {1, -3}, {2, 4000000}},
+			wantInt:  6,
+			wantBool: true,
+		},
+		{
+			name:     "inner syllable must match exactly",
+			sub:      RawWord{{0, 1}, {1, -3}, {2, 1}},
+			whole:    RawWord{{0, 1000000}, {1, -4}, {2, 1000000}},
+			wantInt:  -1,
+			wantBool: false,
+		},
+		{
+			name:     "signs must agree",
+			sub:      RawWord{{0, 2}},
+			whole:    RawWord{{1, 1}, {0, -1000000}},
+			wantInt:  -1,
+			wantBool: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInt, gotBool := p.SubRawWordFirstMatch(tt.sub, tt.whole)
+			if gotInt != tt.wantInt || gotBool != tt.wantBool {
+				t.Fatalf("SubRawWordFirstMatch(%v, %v) = %v, %v, want %v, %v", tt.sub, tt.whole, gotInt, gotBool, tt.wantInt, tt.wantBool)
+			}
+		})
+	}
+}
+
+func TestCyclicSearchSubRawWord(t *testing.T) {
+	tests := []struct {
+		name  string
+		sub   RawWord
+		whole RawWord
+		want  []int
+	}{
+		{
+			name:  "no wrap-around",
+			sub:   RawWord{{1, 1}},
+			whole: RawWord{{0, 1}, {1, 2}},
+			want:  []int{1, 2},
+		},
+		{
+			name:  "wrap-around",
+			sub:   RawWord{{1, 1}, {0, 2}},
+			whole: RawWord{{0, 1}, {2, 1}, {1, 1}, {0, 1}},
+			want:  []int{2},
+		},
+		{
+			name:  "longer than the word",
+			sub:   RawWord{{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}},
+			whole: RawWord{{0, 1}, {1, 1}},
+			want:  []int{0},
+		},
+		{
+			name:  "empty pattern",
+			sub:   RawWord{},
+			whole: RawWord{{3, -3}},
+			want:  []int{0, 1, 2},
+		},
+		{
+			name:  "no match",
+			sub:   RawWord{{0, -1}},
+			whole: RawWord{{0, 1}, {1, -4}},
+			want:  []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.CyclicSearchSubRawWord(tt.sub, tt.whole)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("CyclicSearchSubRawWord(%v, %v) = %v, want %v", tt.sub, tt.whole, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCyclicInvSearchSubRawWord(t *testing.T) {
+	// r = [a,b] = a^-1b^-1ab, whose inverse is b^-1a^-1ba
+	r := RawWord{{0, -1}, {1, -1}, {0, 1}, {1, 1}}
+	got := p.CyclicInvSearchSubRawWord(RawWord{{1, 1}}, r)
+	want := []p.CyclicMatch{{Index: 3}, {Index: 2, Inverse: true}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("CyclicInvSearchSubRawWord = %v, want %v", got, want)
+	}
+	if !p.IsCyclicSubWord(p.NewWord(RawWord{{0, 1}, {1, -1}}), p.NewWord(r), true) {
+		t.Fatalf("ab^-1 is a subword of a cyclic permutation of r^-1")
+	}
+	if p.IsCyclicSubWord(p.NewWord(RawWord{{0, 1}, {1, -1}}), p.NewWord(r), false) {
+		t.Fatalf("ab^-1 is not a subword of a cyclic permutation of r")
+	}
+}
+
+// the cyclic search should agree with a linear search in the word repeated enough times
+func TestCyclicSearchSubAgainstKMP(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	for range 500 {
+		sub, whole := RawWord{}, RawWord{}
+		for range r.IntN(5) {
+			sub = append(sub, [2]int{r.IntN(2), 2*r.IntN(2) - 1})
+		}
+		for range r.IntN(6) + 1 {
+			whole = append(whole, [2]int{r.IntN(2), 2*r.IntN(2) - 1})
+		}
+		repeated := RawWord{}
+		for range len(sub)/len(whole) + 2 {
+			repeated = append(repeated, whole...)
+		}
+		want := []int{}
+		for _, i := range p.KMPSearchSub(sub, repeated) {
+			if i < len(whole) {
+				want = append(want, i)
+			}
+		}
+		if got := p.CyclicSearchSubIdx(p.IndexableSlice[[2]int](sub), p.IndexableSlice[[2]int](whole)); !slices.Equal(got, want) {
+			t.Fatalf("CyclicSearchSubIdx(%v, %v) = %v, want %v", sub, whole, got, want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package presentation
+
+import "context"
+
+// Todd-Coxeter coset enumeration, in the HLT (Haselgrove, Leech, Trotter) style, see Holt, Handbook of Computational Group Theory, 5.1
+// It builds the action of G on the right cosets of a subgroup H, defining new cosets as needed and identifying cosets when a relator says they are equal
+// This finishes exactly when H has finite index, but we don't know how many cosets it needs on the way, hence the limit
+// With H trivial, a complete table is the regular representation of the finite group G, which solves its word problem
+
+// The action of the generators of G on the right cosets of a subgroup, coset 0 being the subgroup itself
+// Columns are the symbols of fsa: generator g is column 2g and its inverse column 2g+1
+type CosetTable struct {
+	gen   int
+	table [][]int
+}
+
+// the number of cosets, i.e. the index of the subgroup
+func (T *CosetTable) Index() int {
+	return len(T.table)
+}
+
+// Act returns the coset c w
+// Precondition: w uses only generators of the presentation the table was built from
+func (T *CosetTable) Act(c int, w Word) int {
+	for _, u := range w.seq {
+		col := 2 * u[0]
+		if u[1] < 0 {
+			col++
+		}
+		for range abs(u[1]) {
+			c = T.table[c][col]
+		}
+	}
+	return c
+}
+
+// a table being built, where -1 means undefined and p is a union find forest of the coincidences
+type cosetEnumeration struct {
+	table [][]int
+	p     []int
+	queue []int //cosets found equal to a smaller one, whose rows still need to be moved
+}
+
+func (e *cosetEnumeration) define(c, x int) {
+	d := len(e.table)
+	row := make([]int, len(e.table[0]))
+	for i := range row {
+		row[i] = -1
+	}
+	e.table, e.p = append(e.table, row), append(e.p, d)
+	e.table[c][x], e.table[d][x^1] = d, c
+}
+
+func (e *cosetEnumeration) rep(c int) int {
+	r := c
+	for e.p[r] != r {
+		r = e.p[r]
+	}
+	for e.p[c] != r { //path compression
+		e.p[c], c = r, e.p[c]
+	}
+	return r
+}
+
+func (e *cosetEnumeration) merge(k, l int) {
+	k, l = e.rep(k), e.rep(l)
+	if k == l {
+		return
+	} else if k > l {
+		k, l = l, k
+	}
+	e.p[l] = k
+	e.queue = append(e.queue, l)
+}
+
+// makes a and b equal, along with all the cosets this forces to be equal
+func (e *cosetEnumeration) coincidence(a, b int) {
+	e.queue = e.queue[:0]
+	e.merge(a, b)
+	for i := 0; i < len(e.queue); i++ {
+		c := e.queue[i]
+		for x, d := range e.table[c] {
+			if d < 0 {
+				continue
+			}
+			e.table[d][x^1] = -1
+			c1, d1 := e.rep(c), e.rep(d)
+			if t := e.table[c1][x]; t >= 0 {
+				e.merge(d1, t)
+			} else if t := e.table[d1][x^1]; t >= 0 {
+				e.merge(c1, t)
+			} else {
+				e.table[c1][x], e.table[d1][x^1] = d1, c1
+			}
+		}
+	}
+}
+
+// traces w (columns) from c both ways, defining cosets until it closes into a loop
+func (e *cosetEnumeration) scanAndFill(c int, w []int) {
+	f, b := c, c
+	i, j := 0, len(w)-1
+	for {
+		for i <= j && e.table[f][w[i]] >= 0 {
+			f, i = e.table[f][w[i]], i+1
+		}
+		if i > j {
+			if f != b {
+				e.coincidence(f, b)
+			}
+			return
+		}
+		for j >= i && e.table[b][w[j]^1] >= 0 {
+			b, j = e.table[b][w[j]^1], j-1
+		}
+		if j < i {
+			e.coincidence(f, b)
+			return
+		} else if i == j { //deduction
+			e.table[f][w[i]], e.table[b][w[i]^1] = b, f
+			return
+		}
+		e.define(f, w[i])
+	}
+}
+
+// the columns of the letters of w
+func cosetColumns(w RawWord) []int {
+	letters := expandRawWord(ReduceRawWord(w))
+	cols := make([]int, len(letters))
+	for i, x := range letters {
+		cols[i] = 2 * x[0]
+		if x[1] < 0 {
+			cols[i]++
+		}
+	}
+	return cols
+}
+
+// ToddCoxeter enumerates the cosets of the subgroup generated by subgroup, defining at most limits.MaxCosets cosets along the way (no limit if 0)
+// Returns a *LimitError (see ErrCosetLimit) when it needs more, and ctx.Err() when ctx is done first
+// Since the enumeration never ends when the index is infinite, give ctx a deadline or set limits
+func (G *GroupPresentation) ToddCoxeter(ctx context.Context, subgroup []Word, limits Limits) (*CosetTable, error) {
+	maxCosets := newBudget(ctx, limits).limits.MaxCosets
+	for _, h := range subgroup {
+		if err := G.IsValidWord(h); err != nil {
+			return nil, err
+		}
+	}
+	if G.gen == 0 {
+		return &CosetTable{table: [][]int{{}}}, nil
+	}
+	e := &cosetEnumeration{}
+	e.table, e.p = [][]int{make([]int, 2*G.gen)}, []int{0}
+	for i := range e.table[0] {
+		e.table[0][i] = -1
+	}
+	for _, h := range subgroup {
+		e.scanAndFill(0, cosetColumns(h.seq))
+	}
+	rel := G.relators()
+	relators := make([][]int, 0, len(rel))
+	for _, r := range rel {
+		relators = append(relators, cosetColumns(r.seq))
+	}
+	for c := 0; c < len(e.table); c++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, r := range relators {
+			if e.p[c] != c {
+				break
+			}
+			e.scanAndFill(c, r)
+		}
+		for x := range e.table[c] {
+			if e.p[c] == c && e.table[c][x] < 0 {
+				e.define(c, x)
+			}
+		}
+		if maxCosets >= 0 && len(e.table) > maxCosets {
+			return nil, &LimitError{Budget: CosetBudget, Limit: maxCosets}
+		}
+	}
+	// renumber the cosets left
+	ids := make([]int, len(e.table))
+	T := &CosetTable{gen: G.gen}
+	for c := range e.table {
+		if e.p[c] == c {
+			ids[c] = len(T.table)
+			T.table = append(T.table, e.table[c])
+		}
+	}
+	for _, row := range T.table {
+		for x := range row {
+			row[x] = ids[e.rep(row[x])]
+		}
+	}
+	return T, nil
+}
@@ -0,0 +1,68 @@
+package presentation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestToddCoxeter(t *testing.T) {
+	s3 := []RawWord{{{0, 2}}, {{1, 2}}, {{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}}
+	tests := []struct {
+		name     string
+		gen      int
+		rel      []RawWord
+		subgroup []RawWord
+		index    int
+	}{
+		{"trivial group", 0, nil, nil, 1},
+		{"Z/5", 1, []RawWord{{{0, 5}}}, nil, 5},
+		{"S3", 2, s3, nil, 6},
+		{"S3 over <a>", 2, s3, []RawWord{{{0, 1}}}, 3},
+		{"S3 over <ab>", 2, s3, []RawWord{{{0, 1}, {1, 1}}}, 2},
+		{"A5", 2, []RawWord{{{0, 2}}, {{1, 3}}, {{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}}, nil, 60},
+		{"Z^2 over <a, b^3>", 2, []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -1}}}, []RawWord{{{0, 1}}, {{1, 3}}}, 3},
+		// a b a^-1 = b^2 and b a b^-1 = a^2 present the trivial group
+		{"trivial group in disguise", 2, []RawWord{{{0, 1}, {1, 1}, {0, -1}, {1, -2}}, {{1, 1}, {0, 1}, {1, -1}, {0, -2}}}, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet(wordsOf(tt.rel)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			T, err := G.ToddCoxeter(context.Background(), wordsOf(tt.subgroup), p.Limits{}) //finite index, so no limit is needed
+			if err != nil {
+				t.Fatal(err)
+			}
+			if T.Index() != tt.index {
+				t.Errorf("index %v, want %v", T.Index(), tt.index)
+			}
+			// the subgroup fixes coset 0 and relators fix every coset
+			for _, h := range tt.subgroup {
+				if T.Act(0, p.NewWord(h)) != 0 {
+					t.Errorf("%v moves the subgroup", h)
+				}
+			}
+			for c := range T.Index() {
+				for _, r := range tt.rel {
+					if T.Act(c, p.NewWord(r)) != c {
+						t.Errorf("%v moves coset %v", r, c)
+					}
+				}
+			}
+		})
+	}
+
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet(nil))
+	if _, err := G.ToddCoxeter(context.Background(), nil, p.Limits{MaxCosets: 1000}); !errors.Is(err, p.ErrCosetLimit) {
+		t.Errorf("got error %v, want ErrCosetLimit", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := G.ToddCoxeter(ctx, nil, p.Limits{}); err != context.Canceled {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,51 @@
+package presentation
+
+// One-relator groups with torsion are those whose relator is a proper power r^n with n >= 2 (Karrass, Magnus and Solitar)
+// Then r has order exactly n, and the group is hyperbolic
+// B.B. Newman's spelling theorem: a freely reduced nonempty word which is trivial in <X | r^n> contains a subword of a cyclic conjugate of r^n or r^-n
+// of length more than (n-1)/n times the length of r^n
+// So we get a Dehn algorithm: replace such a subword u of a cyclic conjugate u v of r^±n by the shorter v^-1 until there is none,
+// and w is trivial exactly when it ends up empty
+
+// TorsionElement returns the root r of the relator r^n of a one-relator group with torsion and its order n >= 2, and false if G isn't one
+func (G *GroupPresentation) TorsionElement() (Word, int, bool) {
+	if G.torsionOrder == 0 {
+		return EmptyWord(), 0, false
+	}
+	return G.torsionRoot, G.torsionOrder, true
+}
+
+// records the root and its order if the single relator of G is a proper power, with the rules of the Newman reduction
+func (G *GroupPresentation) detectTorsion() error {
+	var r Word
+	for _, w := range G.rel {
+		r = w
+	}
+	root, n, ok := FindPrimitiveRootWord(r)
+	if !ok {
+		return nil
+	}
+	G.torsionRoot, G.torsionOrder = root, n
+	G.newman, _ = newmanRules(root.seq, n).Compile(LeftmostFirst) //same number of sides
+	return G.addClasses(oneRelatorTorsionGroupClasses)
+}
+
+// the rules u -> v^-1 for the cyclic conjugates u v of r^n and r^-n where u has (n-1)|r| + 1 letters, r being cyclically reduced
+func newmanRules(r RawWord, n int) RewritingSystem {
+	reduced, _ := CyclicReduceRawWord(r)
+	power := RawWord{}
+	for range n {
+		power = ConcatRawWord(power, reduced)
+	}
+	return cyclicConjugateRules(power, (n-1)*len(expandRawWord(reduced))+1)
+}
+
+// NewmanReduce applies the Newman reduction to w, which gives the empty word exactly when w is trivial (see TorsionElement)
+// Other words are not given unique normal forms, but are never longer than the free reduction of w
+// For presentations which are not one-relator groups with torsion, this only freely reduces w
+func (G *GroupPresentation) NewmanReduce(w Word) Word {
+	if G.newman == nil {
+		return ReduceWord(w)
+	}
+	return NewWord(G.newman.Rewrite(w.seq))
+}
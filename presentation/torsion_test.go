@@ -0,0 +1,89 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+func TestTorsionElement(t *testing.T) {
+	tests := []struct {
+		name      string
+		gen       int
+		rel       RawWord
+		wantRoot  RawWord
+		wantOrder int
+	}{
+		{"(ab)^3", 2, RawWord{{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}, RawWord{{0, 1}, {1, 1}}, 3},
+		{"a^5", 1, RawWord{{0, 5}}, RawWord{{0, 1}}, 5},
+		{"[a, b]^2", 2, RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}, {0, 1}, {1, 1}, {0, -1}, {1, -1}}, RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}}, 2},
+		{"[a, b]", 2, RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}}, nil, 0},
+		{"a^2 b^2", 2, RawWord{{0, 2}, {1, 2}}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, err := p.NewGroupPresentation(tt.gen, p.NewWordSet([]p.Word{p.NewWord(tt.rel)}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			root, n, ok := G.TorsionElement()
+			if ok != (tt.wantOrder > 0) || n != tt.wantOrder {
+				t.Fatalf("TorsionElement() = %v, %v, %v, want order %v", root, n, ok, tt.wantOrder)
+			}
+			if G.Classes()[p.OneRelatorTorsion] != ok || G.Classes()[p.Hyperbolic] != ok {
+				t.Errorf("classes %v", G.Classes())
+			}
+			if !ok {
+				return
+			}
+			// the root is only determined up to conjugacy and inversion
+			power := p.EmptyWord()
+			for k := 1; k <= n; k++ {
+				power = p.ConcatWord(power, root)
+				if G.Equal(power, p.EmptyWord()) != (k == n) {
+					t.Errorf("root %v does not have order %v", root, n)
+				}
+			}
+			if root.Len() != p.NewWord(tt.wantRoot).Len() {
+				t.Errorf("root %v, want a conjugate of %v", root, tt.wantRoot)
+			}
+		})
+	}
+}
+
+func TestNewmanReduce(t *testing.T) {
+	tests := []struct {
+		name string
+		gen  int
+		rel  RawWord
+	}{
+		{"(ab)^3", 2, RawWord{{0, 1}, {1, 1}, {0, 1}, {1, 1}, {0, 1}, {1, 1}}},
+		{"(a b a^-1 b^-2)^2", 2, RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -2}, {0, 1}, {1, 1}, {0, -1}, {1, -2}}},
+		{"[a, b]^2 with a free generator", 3, RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}, {0, 1}, {1, 1}, {0, -1}, {1, -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G, _ := p.NewGroupPresentation(tt.gen, p.NewWordSet([]p.Word{p.NewWord(tt.rel)}))
+			rng := rand.New(rand.NewPCG(5, 6))
+			for range 50 {
+				w := p.NewWord(productOfConjugates(rng, tt.rel, tt.gen))
+				if got := G.NewmanReduce(w); p.CompactLen(got) != 0 {
+					t.Errorf("NewmanReduce(%v) = %v, want the empty word", w, got)
+				}
+			}
+			// cross check with the Magnus breakdown, which doesn't use the torsion
+			for range 200 {
+				w := p.NewWord(randomRawWord(rng, tt.gen, 10))
+				want, err := G.IsTrivialOneRelator(w)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, _ := G.Reduce(w)
+				if (p.CompactLen(got) == 0) != want {
+					t.Errorf("Reduce(%v) = %v, but trivial is %v", w, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package presentation
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/word"
+)
+
+// Conversions between the slice based Word and the tree based word.Word
+// The tree based words are meant for huge words (e.g. large powers) that would be too costly to store as slices
+
+// converts a Word into a (freely reduced) tree word
+func WordToTree(w Word) *word.Word {
+	return word.FromRaw(w.seq)
+}
+
+// converts a tree word into a Word
+// WARNING: this materializes every syllable of t
+func TreeToWord(t *word.Word) Word {
+	return NewWord(t.Raw())
+}
+
+// same as IsValidWord but for tree words, in time proportional to the size of the tree rather than the length of the word
+func (G *GroupPresentation) IsValidTree(t *word.Word) error {
+	for g := range t.ExponentSums() {
+		if g >= G.gen || g < 0 {
+			return fmt.Errorf("invalid generator %v in tree word", g)
+		}
+	}
+	return nil
+}
+
+// ReduceTree is Reduce for tree words
+// Free and free abelian presentations are handled without expanding t, other classes go through Reduce (and thus expand t)
+func (G *GroupPresentation) ReduceTree(t *word.Word) (*word.Word, error) {
+	if err := G.IsValidTree(t); err != nil {
+		return word.Identity(), err
+	}
+	switch {
+	case G.hasClass(Trivial):
+		return word.Identity(), nil
+	case G.hasClass(FreeAbelian):
+		sums := t.ExponentSums()
+		gens := make([]int, 0, len(sums))
+		for g := range sums {
+			gens = append(gens, g)
+		}
+		slices.Sort(gens)
+		reduced := word.Identity()
+		for _, g := range gens {
+			reduced = word.Concat(reduced, word.Letter(g, sums[g]))
+		}
+		return reduced, nil
+	case G.hasClass(Free):
+		return t, nil //tree words are always freely reduced
+	}
+	reduced, err := G.Reduce(TreeToWord(t))
+	if err != nil {
+		return word.Identity(), err
+	}
+	return WordToTree(reduced), nil
+}
@@ -0,0 +1,83 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+	"github.com/geometricgrouptheorydev/groups-in-go/word"
+)
+
+// tree words should agree with the slice based operations
+func TestTreeWordOperations(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	randomWord := func() RawWord {
+		w := RawWord{}
+		for range r.IntN(8) {
+			w = append(w, [2]int{r.IntN(3), r.IntN(5) - 2})
+		}
+		return w
+	}
+	for range 300 {
+		u, v := randomWord(), randomWord()
+		n := r.IntN(7) - 3
+		tu, tv := word.FromRaw(u), word.FromRaw(v)
+		checks := []struct {
+			name string
+			got  *word.Word
+			want RawWord
+		}{
+			{"FromRaw", tu, p.ReduceRawWord(u)},
+			{"Concat", word.Concat(tu, tv), p.ReduceRawWord(p.ConcatRawWord(u, v))},
+			{"Inv", word.Inv(tu), p.ReduceRawWord(p.InvRawWord(u))},
+			{"Pow", word.Pow(tu, n), p.ReduceRawWord(p.PowRawWord(n, u))},
+			{"Concat with inverse power", word.Concat(word.Pow(tu, 3), word.Pow(tu, -2)), p.ReduceRawWord(u)},
+		}
+		for _, c := range checks {
+			if !p.EqualRawWord(c.got.Raw(), c.want) {
+				t.Fatalf("%v on %v, %v (n = %v) = %v want %v", c.name, u, v, n, c.got.Raw(), c.want)
+			}
+			if c.got.Len() != p.NewWord(c.want).Len() {
+				t.Fatalf("%v on %v, %v has length %v want %v", c.name, u, v, c.got.Len(), p.NewWord(c.want).Len())
+			}
+			if !word.Equal(c.got, word.FromRaw(c.want)) {
+				t.Fatalf("%v on %v, %v is not Equal to FromRaw(%v)", c.name, u, v, c.want)
+			}
+		}
+	}
+}
+
+func TestReduceTree(t *testing.T) {
+	huge := word.Pow(word.FromRaw(RawWord{{0, 1}, {1, 2}}), 1000000000) //(ab^2)^(10^9)
+	if huge.Len() != 3000000000 || huge.At(2999999999) != [2]int{1, 1} {
+		t.Fatalf("(ab^2)^(10^9) has length %v and last letter %v", huge.Len(), huge.At(2999999999))
+	}
+
+	F, err := p.NewFreeGroup(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := F.ReduceTree(word.Concat(huge, word.Pow(word.FromRaw(RawWord{{1, -2}, {0, -1}}), 999999999)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !word.Equal(got, word.FromRaw(RawWord{{0, 1}, {1, 2}})) {
+		t.Fatalf("free reduction gave %v want [[0 1] [1 2]]", got.Raw())
+	}
+
+	A, err := p.NewFreeAbelianGroup(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = A.ReduceTree(word.Concat(huge, word.Letter(0, 5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !word.Equal(got, word.FromRaw(RawWord{{0, 1000000005}, {1, 2000000000}})) {
+		t.Fatalf("free abelian reduction gave %v", got.Raw())
+	}
+
+	if _, err := F.ReduceTree(word.Letter(2, 1)); err == nil {
+		t.Fatal("expected an error for an out of range generator")
+	}
+}
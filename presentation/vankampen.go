@@ -0,0 +1,384 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+)
+
+// Van Kampen diagrams: a word w is trivial in G exactly when it is the boundary of a planar 2-complex (a disc diagram)
+// whose edges are labeled by generators and whose faces read relators around their boundary
+// The area of w is the least number of faces of such a diagram, and the Dehn function of G sends n to the largest area of trivial words of length at most n
+// It is linear exactly when G is hyperbolic, and e.g. quadratic for Z^2
+//
+// We build diagrams from certificates (see Certify): each factor c^-1 r^e c is a lollipop, with a stem reading c^-1 from the base vertex and a face reading r^e,
+// so the boundary of the bouquet of lollipops reads the product of the factors
+// Then we fold consecutive boundary edges reading x x^-1, as in the free reduction of the boundary word, which keeps the diagram planar:
+// - if it is the same edge read both ways, it is a spur that we remove
+// - if the edges have different ends, we glue them together along with their ends
+// - if they have the same ends, they bound a disc whose inside only touches the rest of the diagram at that vertex, and we remove it along with the two edges
+// The diagrams have no reason to have the least area, so they only give upper bounds on the area
+
+// The edge reads x_Generator from From to To
+type DiagramEdge struct {
+	From      int `json:"from"`
+	To        int `json:"to"`
+	Generator int `json:"generator"`
+}
+
+// Reading an edge, forwards (x_Generator) or backwards (x_Generator^-1)
+type DiagramStep struct {
+	Edge    int  `json:"edge"`
+	Forward bool `json:"forward"`
+}
+
+// A face reading Relator^Exponent around its boundary
+type DiagramFace struct {
+	Relator  RawWord       `json:"relator"`
+	Exponent int           `json:"exponent"`
+	Boundary []DiagramStep `json:"boundary"`
+}
+
+// A van Kampen diagram with vertices 0, ..., Vertices-1, whose boundary reads the freely reduced word from Base
+// The fields have JSON tags, so encoding/json exports diagrams
+type VanKampenDiagram struct {
+	Vertices int           `json:"vertices"`
+	Base     int           `json:"base"`
+	Edges    []DiagramEdge `json:"edges"`
+	Faces    []DiagramFace `json:"faces"`
+	Boundary []DiagramStep `json:"boundary"`
+}
+
+// the number of faces
+func (D *VanKampenDiagram) Area() int {
+	return len(D.Faces)
+}
+
+// the largest distance between two vertices in the 1-skeleton
+func (D *VanKampenDiagram) Diameter() int {
+	adj := make([][]int, D.Vertices)
+	for _, e := range D.Edges {
+		adj[e.From], adj[e.To] = append(adj[e.From], e.To), append(adj[e.To], e.From)
+	}
+	diameter := 0
+	for v := range D.Vertices {
+		dist := make([]int, D.Vertices)
+		for i := range dist {
+			dist[i] = -1
+		}
+		dist[v] = 0
+		queue := []int{v}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			diameter = max(diameter, dist[u])
+			for _, t := range adj[u] {
+				if dist[t] < 0 {
+					dist[t] = dist[u] + 1
+					queue = append(queue, t)
+				}
+			}
+		}
+	}
+	return diameter
+}
+
+// the word read along steps
+func (D *VanKampenDiagram) label(steps []DiagramStep) RawWord {
+	w := make(RawWord, len(steps))
+	for i, s := range steps {
+		w[i] = [2]int{D.Edges[s.Edge].Generator, 1}
+		if !s.Forward {
+			w[i][1] = -1
+		}
+	}
+	return ReduceRawWord(w)
+}
+
+// BoundaryWord is the word read along the boundary from the base vertex
+func (D *VanKampenDiagram) BoundaryWord() Word {
+	return NewWord(D.label(D.Boundary))
+}
+
+// a diagram being folded, where vertices and edges are merged with union find forests
+type diagramBuilder struct {
+	edges     []DiagramEdge
+	faces     []DiagramFace
+	vertexRep []int
+	edgeRep   []int
+	deadEdge  []bool
+	deadFace  []bool
+	faceCount []int //the number of live faces along each edge, counted with multiplicity
+}
+
+func (b *diagramBuilder) vertex() int {
+	b.vertexRep = append(b.vertexRep, len(b.vertexRep))
+	return len(b.vertexRep) - 1
+}
+
+func findRep(rep []int, x int) int {
+	for rep[x] != x {
+		rep[x], x = rep[rep[x]], rep[x]
+	}
+	return x
+}
+
+// a new edge from v reading the letter x, to a new vertex unless to >= 0
+func (b *diagramBuilder) edge(v int, x [2]int, to int) DiagramStep {
+	if to < 0 {
+		to = b.vertex()
+	}
+	e := DiagramEdge{From: v, To: to, Generator: x[0]}
+	if x[1] < 0 {
+		e.From, e.To = to, v
+	}
+	b.edges, b.edgeRep = append(b.edges, e), append(b.edgeRep, len(b.edges))
+	b.deadEdge, b.faceCount = append(b.deadEdge, false), append(b.faceCount, 0)
+	return DiagramStep{Edge: len(b.edges) - 1, Forward: x[1] > 0}
+}
+
+// the start and end of a step
+func (b *diagramBuilder) ends(s DiagramStep) (int, int) {
+	e := b.edges[findRep(b.edgeRep, s.Edge)]
+	from, to := findRep(b.vertexRep, e.From), findRep(b.vertexRep, e.To)
+	if !s.Forward {
+		return to, from
+	}
+	return from, to
+}
+
+func (b *diagramBuilder) letter(s DiagramStep) [2]int {
+	x := [2]int{b.edges[s.Edge].Generator, 1}
+	if !s.Forward {
+		x[1] = -1
+	}
+	return x
+}
+
+// adds the lollipop of c^-1 r^e c at the base vertex 0, returning the steps along its boundary
+func (b *diagramBuilder) lollipop(f ConjugatedRelator) []DiagramStep {
+	stem := make([]DiagramStep, 0)
+	v := 0
+	for _, x := range expandRawWord(ReduceRawWord(InvRawWord(f.Conjugator.seq))) {
+		s := b.edge(v, x, -1)
+		_, v = b.ends(s)
+		stem = append(stem, s)
+	}
+	r := f.Relator.seq
+	if f.Exponent < 0 {
+		r = InvRawWord(r)
+	}
+	letters := expandRawWord(r)
+	loop := make([]DiagramStep, len(letters))
+	u := v
+	for i, x := range letters {
+		to := -1
+		if i == len(letters)-1 {
+			to = v
+		}
+		loop[i] = b.edge(u, x, to)
+		_, u = b.ends(loop[i])
+		b.faceCount[loop[i].Edge]++
+	}
+	b.faces, b.deadFace = append(b.faces, DiagramFace{Relator: f.Relator.seq, Exponent: f.Exponent, Boundary: loop}), append(b.deadFace, false)
+	steps := append(append([]DiagramStep{}, stem...), loop...)
+	for i := len(stem) - 1; i >= 0; i-- {
+		steps = append(steps, DiagramStep{Edge: stem[i].Edge, Forward: !stem[i].Forward})
+	}
+	return steps
+}
+
+// removes everything enclosed by the two steps s and t, which both go from a to a, as well as their edges
+// walk holds the other boundary steps: the inside doesn't touch the boundary, and only meets the outside at a
+func (b *diagramBuilder) removeDisc(s, t DiagramStep, a int, walk []DiagramStep) {
+	e1, e2 := findRep(b.edgeRep, s.Edge), findRep(b.edgeRep, t.Edge)
+	// components of live edges, connected through vertices other than a and through faces
+	comp := make([]int, len(b.edges))
+	for e := range comp {
+		comp[e] = e
+	}
+	union := func(x, y int) { comp[findRep(comp, x)] = findRep(comp, y) }
+	byVertex := make(map[int]int)
+	for e := range b.edges {
+		if b.deadEdge[e] || findRep(b.edgeRep, e) != e || e == e1 || e == e2 {
+			continue
+		}
+		for _, v := range []int{b.edges[e].From, b.edges[e].To} {
+			if v = findRep(b.vertexRep, v); v == a {
+				continue
+			} else if f, ok := byVertex[v]; ok {
+				union(e, f)
+			} else {
+				byVertex[v] = e
+			}
+		}
+	}
+	for i, f := range b.faces {
+		if b.deadFace[i] {
+			continue
+		}
+		for _, step := range f.Boundary {
+			if e := findRep(b.edgeRep, step.Edge); e != e1 && e != e2 {
+				union(e, findRep(b.edgeRep, f.Boundary[0].Edge))
+			}
+		}
+	}
+	outside := make(map[int]bool)
+	for _, step := range walk {
+		outside[findRep(comp, findRep(b.edgeRep, step.Edge))] = true
+	}
+	for e := range b.edges {
+		if !b.deadEdge[e] && findRep(b.edgeRep, e) == e && (e == e1 || e == e2 || !outside[findRep(comp, e)]) {
+			b.deadEdge[e] = true
+		}
+	}
+	for i, f := range b.faces {
+		for _, step := range f.Boundary {
+			if b.deadEdge[findRep(b.edgeRep, step.Edge)] {
+				b.deadFace[i] = true
+			}
+		}
+	}
+}
+
+// NewVanKampenDiagram builds a van Kampen diagram for c.U c.V^-1 from the certificate c, which must pass CheckCertificate
+func (G *GroupPresentation) NewVanKampenDiagram(c *Certificate) (*VanKampenDiagram, error) {
+	if err := G.CheckCertificate(c); err != nil {
+		return nil, err
+	}
+	b := &diagramBuilder{}
+	b.vertex() //the base vertex 0
+	input := make([]DiagramStep, 0)
+	for _, f := range c.Factors {
+		input = append(input, b.lollipop(f)...)
+	}
+	// fold the boundary like the free reduction of its word, with a stack of the steps kept so far
+	walk := make([]DiagramStep, 0, len(input))
+	for i, s := range input {
+		s.Edge = findRep(b.edgeRep, s.Edge)
+		if len(walk) == 0 || b.letter(walk[len(walk)-1]) != InvLetter(b.letter(s)) {
+			walk = append(walk, s)
+			continue
+		}
+		t := walk[len(walk)-1]
+		walk = walk[:len(walk)-1]
+		t.Edge = findRep(b.edgeRep, t.Edge)
+		a, _ := b.ends(t)
+		_, z := b.ends(s)
+		switch {
+		case t.Edge == s.Edge: //a spur
+			if b.faceCount[t.Edge] == 0 {
+				b.deadEdge[t.Edge] = true
+			}
+		case a != z: //glue s onto t backwards
+			b.vertexRep[z] = a
+			b.edgeRep[s.Edge] = t.Edge
+			b.faceCount[t.Edge] += b.faceCount[s.Edge]
+		default:
+			b.removeDisc(t, s, a, append(append([]DiagramStep{}, walk...), input[i+1:]...))
+		}
+	}
+	// renumber what is left
+	D := &VanKampenDiagram{}
+	vertexIDs := make(map[int]int)
+	vertexID := func(v int) int {
+		v = findRep(b.vertexRep, v)
+		if _, ok := vertexIDs[v]; !ok {
+			vertexIDs[v] = len(vertexIDs)
+		}
+		return vertexIDs[v]
+	}
+	D.Base = vertexID(0)
+	edgeIDs := make(map[int]int)
+	for e, edge := range b.edges {
+		if !b.deadEdge[e] && findRep(b.edgeRep, e) == e {
+			edgeIDs[e] = len(D.Edges)
+			D.Edges = append(D.Edges, DiagramEdge{From: vertexID(edge.From), To: vertexID(edge.To), Generator: edge.Generator})
+		}
+	}
+	renumber := func(steps []DiagramStep) []DiagramStep {
+		r := make([]DiagramStep, len(steps))
+		for i, s := range steps {
+			r[i] = DiagramStep{Edge: edgeIDs[findRep(b.edgeRep, s.Edge)], Forward: s.Forward}
+		}
+		return r
+	}
+	for i, f := range b.faces {
+		if !b.deadFace[i] {
+			D.Faces = append(D.Faces, DiagramFace{Relator: f.Relator, Exponent: f.Exponent, Boundary: renumber(f.Boundary)})
+		}
+	}
+	D.Boundary = renumber(walk)
+	D.Vertices = len(vertexIDs)
+	return D, nil
+}
+
+// EstimateDehnFunction estimates the Dehn function of G at 0, ..., maxLength from samples random trivial words,
+// which are products of up to maxLength conjugates of relators, freely reduced
+// Each word gets the area of the diagram of the certificate found by Certify with at most maxRules rules, and the estimate at n is the largest area for words of length at most n
+// These are neither upper nor lower bounds on the Dehn function, since diagrams don't have the least area and we don't try every word, but the growth rate tells e.g. hyperbolic groups (linear) from Z^2 (quadratic)
+// Returns ErrNoCertificate if some sampled word has no certificate
+func (G *GroupPresentation) EstimateDehnFunction(maxLength, samples, maxRules int, rng *rand.Rand) ([]int, error) {
+	estimate, err := G.estimateDehnFunction(rulesBudget(context.Background(), maxRules), maxLength, samples, rng)
+	if errors.Is(err, ErrRuleLimit) {
+		return nil, ErrNoCertificate
+	}
+	return estimate, err
+}
+
+// EstimateDehnFunctionContext is EstimateDehnFunction certifying each sample with CertifyContext and limits
+// It returns ctx.Err() or a *LimitError when some sample stops before it is certified
+func (G *GroupPresentation) EstimateDehnFunctionContext(ctx context.Context, maxLength, samples int, limits Limits, rng *rand.Rand) ([]int, error) {
+	return G.estimateDehnFunction(newBudget(ctx, limits), maxLength, samples, rng)
+}
+
+// EstimateDehnFunction with the limits of b for each sample
+func (G *GroupPresentation) estimateDehnFunction(b *budget, maxLength, samples int, rng *rand.Rand) ([]int, error) {
+	if maxLength <= 0 {
+		return make([]int, max(maxLength+1, 0)), nil
+	}
+	rel := G.sortedRelators() //so that the samples only depend on rng
+	relators := make([]RawWord, 0, len(rel))
+	for _, r := range rel {
+		relators = append(relators, r.seq)
+	}
+	estimate := make([]int, maxLength+1)
+	for range samples {
+		if len(relators) == 0 {
+			break
+		}
+		w := RawWord{}
+		for range 1 + rng.IntN(maxLength) {
+			c := make(RawWord, rng.IntN(maxLength/2+1))
+			for i := range c {
+				c[i] = [2]int{rng.IntN(G.gen), 2*rng.IntN(2) - 1}
+			}
+			r := relators[rng.IntN(len(relators))]
+			if rng.IntN(2) == 0 {
+				r = InvRawWord(r)
+			}
+			w = ConcatRawWord(w, ConjugateRawWord(r, c))
+		}
+		w = ReduceRawWord(w)
+		n := NewWord(w).Len()
+		if n > maxLength {
+			continue
+		}
+		if b.stopped(nil) {
+			return nil, b.err
+		}
+		cert, err := G.certify(b, NewWord(w), EmptyWord())
+		if err != nil {
+			return nil, err
+		}
+		D, err := G.NewVanKampenDiagram(cert)
+		if err != nil {
+			return nil, err
+		}
+		estimate[n] = max(estimate[n], D.Area())
+	}
+	for n := 1; n <= maxLength; n++ {
+		estimate[n] = max(estimate[n], estimate[n-1])
+	}
+	return estimate, nil
+}
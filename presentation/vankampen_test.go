@@ -0,0 +1,170 @@
+package presentation_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+// checks that D is a disc diagram for w: its boundary and faces are closed walks reading w and their relators, and V - E + F = 1
+func checkDiagram(t *testing.T, D *p.VanKampenDiagram, w RawWord) {
+	t.Helper()
+	walk := func(start int, steps []p.DiagramStep) (int, RawWord) {
+		v, label := start, RawWord{}
+		for _, s := range steps {
+			e := D.Edges[s.Edge]
+			if s.Forward && e.From == v {
+				v, label = e.To, append(label, [2]int{e.Generator, 1})
+			} else if !s.Forward && e.To == v {
+				v, label = e.From, append(label, [2]int{e.Generator, -1})
+			} else {
+				t.Fatalf("steps %v are not a walk", steps)
+			}
+		}
+		return v, p.ReduceRawWord(label)
+	}
+	if end, label := walk(D.Base, D.Boundary); end != D.Base || !reflect.DeepEqual(label, p.ReduceRawWord(w)) {
+		t.Errorf("boundary reads %v, want %v", label, p.ReduceRawWord(w))
+	}
+	for _, f := range D.Faces {
+		want := f.Relator
+		if f.Exponent < 0 {
+			want = p.InvRawWord(want)
+		}
+		start := D.Edges[f.Boundary[0].Edge].From
+		if !f.Boundary[0].Forward {
+			start = D.Edges[f.Boundary[0].Edge].To
+		}
+		if end, label := walk(start, f.Boundary); end != start || !reflect.DeepEqual(label, p.ReduceRawWord(want)) {
+			t.Errorf("face reads %v, want %v", label, want)
+		}
+	}
+	if chi := D.Vertices - len(D.Edges) + D.Area(); chi != 1 {
+		t.Errorf("Euler characteristic %d, want 1", chi)
+	}
+}
+
+func TestNewVanKampenDiagram(t *testing.T) {
+	commutator := RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}}
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(commutator)}))
+	c, err := G.Certify(p.NewWord(RawWord{{0, 2}, {1, 2}}), p.NewWord(RawWord{{1, 2}, {0, 2}}), 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	D, err := G.NewVanKampenDiagram(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDiagram(t, D, RawWord{{0, 2}, {1, 2}, {0, -2}, {1, -2}})
+	if D.Area() < 4 || D.Area() > len(c.Factors) {
+		t.Errorf("area %d, want between 4 and %d", D.Area(), len(c.Factors))
+	}
+	if D.Diameter() < 2 {
+		t.Errorf("diameter %d, want at least 2", D.Diameter())
+	}
+
+	// a factor followed by its inverse folds away entirely
+	f := p.ConjugatedRelator{Relator: p.NewWord(commutator), Exponent: 1, Conjugator: p.NewWord(RawWord{{1, -1}, {0, 1}})}
+	g := f
+	g.Exponent = -1
+	D, err = G.NewVanKampenDiagram(&p.Certificate{U: p.EmptyWord(), V: p.EmptyWord(), Factors: []p.ConjugatedRelator{f, g}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if D.Vertices != 1 || len(D.Edges) != 0 || D.Area() != 0 || D.Diameter() != 0 {
+		t.Errorf("got %+v, want a single vertex", D)
+	}
+
+	// invalid certificates have no diagram
+	if _, err := G.NewVanKampenDiagram(&p.Certificate{U: p.NewWord(RawWord{{0, 1}}), V: p.EmptyWord()}); err != p.ErrInvalidCertificate {
+		t.Errorf("got error %v, want ErrInvalidCertificate", err)
+	}
+}
+
+func TestNewVanKampenDiagramRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(11, 12))
+	for _, r := range []RawWord{
+		{{0, 1}, {1, 1}, {0, -1}, {1, -1}},
+		{{0, 3}},
+		{{1, 1}, {0, 1}, {1, -1}, {0, -2}},
+	} {
+		G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(r)}))
+		for range 50 {
+			// random factors, with some followed by their inverses
+			factors := make([]p.ConjugatedRelator, 0)
+			product := RawWord{}
+			for range rng.IntN(6) {
+				conj, e := randomRawWord(rng, 2, 3), 2*rng.IntN(2)-1
+				for range 1 + rng.IntN(2) {
+					s := r
+					if e < 0 {
+						s = p.InvRawWord(r)
+					}
+					factors = append(factors, p.ConjugatedRelator{Relator: p.NewWord(r), Exponent: e, Conjugator: p.NewWord(conj)})
+					product = p.ConcatRawWord(product, p.ConjugateRawWord(s, conj))
+					e = -e
+				}
+			}
+			c := &p.Certificate{U: p.NewWord(p.ReduceRawWord(product)), V: p.EmptyWord(), Factors: factors}
+			D, err := G.NewVanKampenDiagram(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDiagram(t, D, product)
+			if D.Area() > len(factors) {
+				t.Errorf("area %d with %d factors", D.Area(), len(factors))
+			}
+		}
+	}
+}
+
+func TestVanKampenDiagramJSON(t *testing.T) {
+	G, _ := p.NewGroupPresentation(2, p.NewWordSet([]p.Word{p.NewWord(RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}})}))
+	c, err := G.Certify(p.NewWord(RawWord{{0, 1}, {1, 1}}), p.NewWord(RawWord{{1, 1}, {0, 1}}), 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	D, err := G.NewVanKampenDiagram(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(D)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var E p.VanKampenDiagram
+	if err := json.Unmarshal(data, &E); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*D, E) {
+		t.Errorf("JSON %s decodes to %+v, want %+v", data, E, *D)
+	}
+}
+
+func TestEstimateDehnFunction(t *testing.T) {
+	// Dehn's algorithm shortens words with each face in a surface group, so the estimate is at most linear
+	surface := RawWord{{0, 1}, {1, 1}, {0, -1}, {1, -1}, {2, 1}, {3, 1}, {2, -1}, {3, -1}}
+	G, _ := p.NewGroupPresentation(4, p.NewWordSet([]p.Word{p.NewWord(surface)}))
+	estimate, err := G.EstimateDehnFunction(24, 200, 0, rand.New(rand.NewPCG(13, 14)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(estimate) != 25 || estimate[24] == 0 {
+		t.Fatalf("got estimate %v", estimate)
+	}
+	for n := range estimate {
+		if estimate[n] > n || n > 0 && estimate[n] < estimate[n-1] {
+			t.Errorf("got estimate %v, want nondecreasing and at most n", estimate)
+			break
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := G.EstimateDehnFunctionContext(ctx, 24, 200, p.Limits{}, rand.New(rand.NewPCG(13, 14))); err != context.Canceled {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
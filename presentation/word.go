@@ -1,5 +1,7 @@
 package presentation
 
+import "sync"
+
 // Slice of (indexed) generator and exponent pairs
 // By convention, generator indices are nonnegative integers. The library does not strictly enforce this, but all built-in constructions only use nonnegative indices.
 // We index from 0 because computers can't count right
@@ -7,37 +9,79 @@ package presentation
 type RawWord [][2]int
 
 // This struct is treated as immutable
-// id permits set-like behavior in word presentations
+// hash permits set-like behavior in word presentations, see WordSet
+// The zero Word is the empty word
 type Word struct {
-	seq     RawWord //seq is short for sequence
-	id      string  //is always equal to WordID(Word.slice)
-	offsets []int   //cumulative expanded lengths
+	seq  RawWord   //seq is short for sequence
+	hash uint64    //is always equal to hashRawWord(Word.seq)
+	lazy *wordLazy //data most words never need, computed on first use
+}
+
+// shared by all copies of a Word so that each field is computed at most once, independently of the other
+type wordLazy struct {
+	idOnce      sync.Once
+	id          string //WordID(Word.seq)
+	offsetsOnce sync.Once
+	offsets     []int //cumulative expanded lengths
 }
 
 // Constructor for a new Word based on a RawWord
 // Most functions on Words call NewWord on the output of its corresponding RawWord version of the function
+// This only hashes w: intermediate words are cheap, and the id and offsets are each computed when first needed
 func NewWord(w [][2]int) Word {
-	// first we calculate the offsets, which is data that speeds up indexing words
-	offsets := make([]int, len(w))
-	total := 0
-	for i, u := range w {
-		total += abs(u[1])
-		offsets[i] = total
-	}
 	return Word{
-		seq:     w,
-		id:      WordID(w),
-		offsets: offsets,
+		seq:  w,
+		hash: hashRawWord(w),
+		lazy: &wordLazy{},
+	}
+}
+
+// the canonical string form of w, see WordID
+func (w Word) id() string {
+	if w.lazy == nil {
+		return WordID(w.seq) //the zero Word
 	}
+	w.lazy.idOnce.Do(func() { w.lazy.id = WordID(w.seq) })
+	return w.lazy.id
 }
 
+// the offsets are data that speeds up indexing words
+func (w Word) offsets() []int {
+	if w.lazy == nil {
+		return nil //the zero Word
+	}
+	w.lazy.offsetsOnce.Do(func() {
+		offsets := make([]int, len(w.seq))
+		total := 0
+		for i, u := range w.seq {
+			total += abs(u[1])
+			offsets[i] = total
+		}
+		w.lazy.offsets = offsets
+	})
+	return w.lazy.offsets
+}
+
+// Hash returns the structural hash of w, equal for equal words, see EqualWord
+// Different words may share a hash, so it is only a shortcut for telling them apart
+func (w Word) Hash() uint64 { return w.hash }
+
+// ID returns the canonical string form of w, see WordID
+func (w Word) ID() string { return w.id() }
+
 func EmptyRawWord() RawWord { return RawWord{} }
 func EmptyWord() Word       { return NewWord(EmptyRawWord()) }
 
 func CompactLen(w Word) int { return len(w.seq) } 
 
 // Gives the true length of a word (sum of absolute exponents of all generators).
-func (w Word)Len() int { return w.offsets[len(w.offsets) - 1] }
+func (w Word)Len() int {
+	offsets := w.offsets()
+	if len(offsets) == 0 {
+		return 0 //empty word
+	}
+	return offsets[len(offsets) - 1]
+}
 
 func ConcatRawWord(a, b RawWord) RawWord { return append(append(RawWord{}, a...), b...) } //double appends for immutability
 func ConcatWord(v, w Word) Word          { return NewWord(ConcatRawWord(v.seq, w.seq)) }
@@ -61,9 +105,9 @@ func EqualRawWord(u, v RawWord) bool {
 	return equalSlices(u, v)
 }
 
-// checks if two Words are equal by comparing their unique IDs
+// checks if two Words are equal by comparing their hashes, then their syllables when the hashes agree
 func EqualWord(u, v Word) bool {
-	return u.id == v.id
+	return u.hash == v.hash && EqualRawWord(u.seq, v.seq)
 }
 
 // invert a RawWord
@@ -94,7 +138,7 @@ func ConjugateWord(w Word, a Word) Word {
 func ReduceRawWord(w RawWord) RawWord {
 	r := make(RawWord, 0, len(w)) //r stands for reduced
 	for _, s := range w {
-		if len(r) > 0 && r[len(r)-1][1] == 0 {
+		if s[1] == 0 {
 			continue //ignore 0 exponents
 		} else if len(r) > 0 && r[len(r)-1][0] == s[0] {
 			if s[1]+r[len(r)-1][1] == 0 {
@@ -120,7 +164,7 @@ func CyclicReduceRawWord(w RawWord) (RawWord, RawWord) {
 	reduced := ReduceRawWord(w) //r for reduced
 	conjugatedBy := make(RawWord, 0, len(reduced))
 	lim := len(reduced)
-	for i := 0; i < lim; i++ {
+	for i := 0; i < lim-1; i++ { //a single syllable is always cyclically reduced
 		s := reduced[i]
 		last := reduced[lim-1]
 		if s[0] == last[0] { //conjugate by a power of s[0]
@@ -128,6 +172,9 @@ func CyclicReduceRawWord(w RawWord) (RawWord, RawWord) {
 			reduced = reduced[:len(reduced)-1]
 			conjugatedBy = append(conjugatedBy, last)
 			lim--
+			if reduced[i][1] != 0 {
+				break //partial cancellation, the new last syllable is a different generator
+			}
 		} else {
 			break //already cyclically reduced
 		}
@@ -162,45 +209,55 @@ func expandRawWord(w RawWord) RawWord {
 	return expanded
 }
 
-// This function outputs the same as expandRawWord(w.seq RawWord)[i][0] * expandRawWord(w.seq RawWord)[i][1] without the memory cost in O(log n) time
-// That is the generator at the ith position but negated if we have its inverse
-// e.g. the value at the 5th index of a^3b^-3c^2, represented as {{0,3},{1,-3},{2,2}} is -1
-// Careful! This function panics on an invalid index.
-func (w Word) At(i int) int {
-	if i < 0 || i >= w.offsets[len(w.offsets)-1] {
-		panic("invalid index")
-	}
-	lo, hi := 0, len(w.offsets)-1
+// index of the syllable of w containing the letter at (expanded) index i, in O(log n) time
+func (w Word) syllableAt(i int) int {
+	offsets := w.offsets()
+	lo, hi := 0, len(offsets)-1
 	for lo < hi {
 		mid := (lo + hi) / 2 //search the index from the middle, then choose a side, then take the middle of that side, and so on
-		if w.offsets[mid] > i {
+		if offsets[mid] > i {
 			hi = mid
 		} else {
 			lo = mid + 1
 		}
 	}
-	val := w.seq[lo]
-	if val[1] < 0 {
-		return -val[0]
+	return lo
+}
+
+// This function outputs the same as expandRawWord(w.seq RawWord)[i] without the memory cost in O(log n) time
+// That is the generator at the ith position together with 1, or -1 if we have its inverse
+// e.g. the value at the 5th index of a^3b^-3c^2, represented as {{0,3},{1,-3},{2,2}} is {1,-1}
+// Careful! This function panics on an invalid index.
+func (w Word) At(i int) [2]int {
+	if i < 0 || i >= w.Len() {
+		panic("invalid index")
 	}
-	return val[0]
+	val := w.seq[w.syllableAt(i)]
+	return [2]int{val[0], sign(val[1])}
 }
 
+// Returns the subword of w from (expanded) index i to j, excluding j, in O(log n + j - i) time at worst
+// Careful! This function panics on invalid indices.
 func (w Word) Slice(i, j int) Word {
-	if i < 0 || j < i || j > w.offsets[len(w.offsets)-1] {
+	if i < 0 || j < i || j > w.Len() {
 		panic("invalid index")
 	}
 	if i == j {
 		return EmptyWord()
 	}
 
-	left, right := largestSmaller(w.offsets, i), smallestLargerOrEq(w.offsets, j)
-	// w.offsets has always 0 as first entry and total length as last entry so left and right are valid indices
-	
+	left, right := w.syllableAt(i), w.syllableAt(j-1)
 	// Copy the range to keep the original Word immutable
-	raw := make([][2]int, right-left)
-	copy(raw, w.seq[left:right])
+	raw := make(RawWord, right-left+1)
+	copy(raw, w.seq[left:right+1])
 	// Adjust the start and end exponents
+	if left == right {
+		raw[0][1] = sign(raw[0][1]) * (j - i)
+		return NewWord(raw)
+	}
+	offsets := w.offsets()
+	raw[0][1] = sign(raw[0][1]) * (offsets[left] - i)
+	raw[len(raw)-1][1] = sign(raw[len(raw)-1][1]) * (j - offsets[right-1])
 	return NewWord(raw)
 }
 
@@ -208,9 +265,9 @@ func (w Word) Slice(i, j int) Word {
 // For example SubWordFirstMatch(NewWord({{1,1},{3,2}}),NewWord({{2,7},{1,3},{3,4}})) returns 9, true
 // Otherwise, return -1 and false (-1 is used as that is not a valid index in Go, always check the boolean to avoid panics)
 // This function can also be used to find any match at all by ignoring the integer returned
-// This function makes use of the Word struct metadata so a RawWord version does not exist
+// See SubRawWordFirstMatch, this works on syllables so it never expands the words
 func SubWordFirstMatch(sub, whole Word) (int, bool) {
-	return KMPSubFirstMatchAt(sub.At, whole.At, sub.Len(), whole.Len())
+	return SubRawWordFirstMatch(sub.seq, whole.seq)
 }
 
 // Use only on RawWords that are already expanded
@@ -219,6 +276,7 @@ func SubExpandedRawWordFirstMatch(sub, whole RawWord) (int, bool) {
 }
 
 // ShortLexRawWord reports whether a < b in shortlex order.
+// This compares syllables (generator, exponent), for the shortlex order on letters and other orderings see WordOrder in order.go
 func ShortLexRawWord(a, b RawWord) bool {
 	if len(a) != len(b) {
 		return len(a) < len(b)
@@ -294,8 +352,9 @@ func CheckIfPowerRawWord(w RawWord) bool {
 	return KMPCheckRepeats(c)
 }
 
+// Words are Indexables, so we don't need to expand them here
 func CheckIfPowerWord(w Word) bool {
-	return CheckIfPowerRawWord(w.seq)
+	return CheckIfPowerIdx(ReduceWord(w), InvLetter)
 }
 
 // A root of a word w is some subword v such that w = v^k for some positive k
@@ -314,6 +373,9 @@ func FindPrimitiveRootRawWord(w RawWord) (RawWord, int, bool) {
 }
 
 func FindPrimitiveRootWord(w Word) (Word, int, bool) {
-	root, exp, ok := FindPrimitiveRootRawWord(w.seq)
-	return NewWord(root), exp, ok
+	root, exp, ok, conj := FindPrimitiveRootIdx(ReduceWord(w), InvLetter)
+	if !ok {
+		return root, 1, false
+	}
+	return ConjugateWord(root, conj), exp, true
 }
@@ -47,5 +47,25 @@ func ParseWordID(id string) (RawWord, error) {
 
 //It is unlikely that the user would truly need this function, nonetheless it is useful for carrying out tests
 func GetWordOffsets(w Word) []int {
-	return w.offsets
+	return w.offsets()
+}
+
+// structural hash of w, mixing in each generator and exponent in turn, see Word
+// the empty word hashes to 0 so that the zero Word is the empty word
+func hashRawWord(w [][2]int) uint64 {
+	var h uint64
+	for _, p := range w {
+		h = mix64(h*0x9e3779b97f4a7c15 + uint64(p[0]))
+		h = mix64(h*0x9e3779b97f4a7c15 + uint64(p[1]))
+	}
+	return h
+}
+
+// the finalizer of splitmix64, a bijection scattering nearby inputs
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
 }
\ No newline at end of file
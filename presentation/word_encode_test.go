@@ -3,6 +3,7 @@ package presentation_test
 import (
 	"testing"
 	"reflect"
+	"sync"
 	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
 )
 
@@ -106,4 +107,25 @@ func TestNewWordOffsets(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// the id and offsets are computed on first use, once for all copies of a word, even from several goroutines
+func TestWordIDLazily(t *testing.T) {
+	raw := RawWord{{0, 2}, {3, -5}, {2, 2}}
+	w := p.NewWord(raw)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if w.ID() != p.WordID(raw) || w.Len() != 9 || w.At(8) != [2]int{2, 1} {
+				t.Errorf("got id %v and length %d for %v", w.ID(), w.Len(), raw)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var zero p.Word
+	if !p.EqualWord(zero, p.EmptyWord()) || zero.ID() != "" || zero.Len() != 0 {
+		t.Errorf("the zero Word is not the empty word")
+	}
+}
@@ -54,6 +54,10 @@ func TestEqualWord(t *testing.T) {
 			if got != tt.want {
 				t.Fatalf("EqualWord(%v, %v) = %v, want %v", tt.first, tt.second, got, tt.want)
 			}
+			u, v := presentation.NewWord(tt.first), presentation.NewWord(tt.second)
+			if got := presentation.EqualWord(u, v); got != tt.want || tt.want && u.Hash() != v.Hash() {
+				t.Fatalf("EqualWord(%v, %v) = %v with hashes %d and %d, want %v", tt.first, tt.second, got, u.Hash(), v.Hash(), tt.want)
+			}
 		})
 	}
 }
@@ -148,6 +152,11 @@ func TestReduce(t *testing.T) {
 			in:   RawWord{{0, 7}, {3, 4}, {2, -4}, {2, 4}, {9, -6}, {9, 6}, {3, -4}, {0, -7}},
 			want: presentation.EmptyRawWord(),
 		},
+		{
+			name: "zero exponents",
+			in:   RawWord{{0, 0}, {1, 2}, {0, 0}, {1, -1}, {2, 0}},
+			want: RawWord{{1, 1}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -191,6 +200,18 @@ func TestCyclicReduce(t *testing.T) {
 			want: RawWord{{0, 1}, {3, 4}, {2, -4}},
 			conj: RawWord{{0, -7}, {4, 2}, {5, -5}},
 		},
+		{
+			name: "partial cancellation stops the reduction",
+			in:   RawWord{{0, 2}, {1, 1}, {2, 1}, {1, -1}, {0, -1}},
+			want: RawWord{{0, 1}, {1, 1}, {2, 1}, {1, -1}},
+			conj: RawWord{{0, -1}},
+		},
+		{
+			name: "single syllable",
+			in:   RawWord{{2, 3}},
+			want: RawWord{{2, 3}},
+			conj: RawWord{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -208,19 +229,25 @@ func TestAt(t *testing.T) {
 		name string
 		word Word
 		index int
-		want int
+		want [2]int
 	}{
 		{
 			name: "positive",
 			word: presentation.NewWord(RawWord{{1,2},{2,-3},{3,4},{5,-2}}),
 			index: 6,
-			want: 3,
+			want: [2]int{3, 1},
 		},
 		{
 			name: "negative",
 			word: presentation.NewWord(RawWord{{1,2},{2,-3},{3,4},{5,-2}}),
 			index: 4,
-			want: -2,
+			want: [2]int{2, -1},
+		},
+		{
+			name: "inverse of generator 0",
+			word: presentation.NewWord(RawWord{{0,2},{0,-1}}),
+			index: 2,
+			want: [2]int{0, -1},
 		},
 	}
 
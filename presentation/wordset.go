@@ -1,7 +1,22 @@
 package presentation
 
-//emulating a mathematical set of relations with a map[string]Word with key always the word.id
-type WordSet map[string]Word 
+//emulating a mathematical set of relations with a hash set of Words, keyed by word.hash
+//distinct words with the same hash go in the next free keys (hash+1, hash+2, ...), so always use the methods below rather than indexing it
+type WordSet map[uint64]Word 
+
+//the key of w in A and true, or the free key where it would go and false
+func (A WordSet) find(w Word) (uint64, bool) {
+	k := w.hash
+	for {
+		v, ok := A[k]
+		if !ok {
+			return k, false
+		} else if EqualWord(v, w) {
+			return k, true
+		}
+		k++
+	}
+}
 
 //build a WordSet from a []Word
 //if len(l) == 0 then this is equivalent to make(WordSet)
@@ -18,10 +33,9 @@ func EqualWordSet(A, B WordSet) bool {
 	if len(A) != len(B) {
 		return false //different lengths
 	}
-	for id := range A {
-		_, ok := B[id]
-		if !ok { 
-			return false //A had an id B does not
+	for _, w := range A {
+		if !B.Has(w) { 
+			return false //A had a word B does not
 		}
 	}
 	return true
@@ -29,19 +43,33 @@ func EqualWordSet(A, B WordSet) bool {
 
 //check whether a word is in a WordSet
 func (A WordSet) Has(w Word) bool {
-	_, ok := A[w.id]
+	_, ok := A.find(w)
 	return ok
 }
 
 //adds a Word to a Wordset
 func (A WordSet) Add(w Word) {
-	A[w.id] = w
+	k, _ := A.find(w)
+	A[k] = w
 }
 
 
 //removes a Word from a Wordset
 func (A WordSet) Remove(w Word) {
-	delete(A, w.id)
+	k, ok := A.find(w)
+	if !ok {
+		return
+	}
+	delete(A, k)
+	//the words after w that were displaced by a collision may now belong in its key
+	for k++; ; k++ {
+		v, ok := A[k]
+		if !ok {
+			return
+		}
+		delete(A, k)
+		A.Add(v)
+	}
 }
 
 //returns a new WordSet that's the union of the inputted two
@@ -0,0 +1,36 @@
+package presentation_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	p "github.com/geometricgrouptheorydev/groups-in-go/presentation"
+)
+
+// a WordSet agrees with a set of ids through random additions and removals
+func TestWordSet(t *testing.T) {
+	rng := rand.New(rand.NewPCG(21, 22))
+	A, ids := p.NewWordSet(nil), make(map[string]bool)
+	for range 2000 {
+		w := p.NewWord(randomRawWord(rng, 2, 2))
+		if rng.IntN(3) == 0 {
+			A.Remove(w)
+			delete(ids, w.ID())
+		} else {
+			A.Add(w)
+			ids[w.ID()] = true
+		}
+		if A.Has(w) != ids[w.ID()] || len(A) != len(ids) {
+			t.Fatalf("after %v, Has = %v and %d words, want %v and %d", w, A.Has(w), len(A), ids[w.ID()], len(ids))
+		}
+	}
+	B := A.Copy()
+	for _, w := range A {
+		if !ids[w.ID()] || !B.Has(w) {
+			t.Errorf("%v should not be in the set", w)
+		}
+	}
+	if !p.EqualWordSet(A, B) || p.EqualWordSet(A, p.Union(B, p.NewWordSet([]p.Word{p.NewWord(RawWord{{5, 1}})}))) {
+		t.Errorf("EqualWordSet disagrees with the copy")
+	}
+}
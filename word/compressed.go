@@ -0,0 +1,139 @@
+package word
+
+import "github.com/geometricgrouptheorydev/groups-in-go/internal/recompression"
+
+// Trees are straight-line programs, so words bracketed differently, such as a(ba)^(n-1)b and (ab)^n, can be compared
+// without expanding them with recompression (see internal/recompression)
+
+// the rules of a straight-line program deriving words, their inverses and their subwords, built from the trees
+type grammar struct {
+	recompression.Grammar
+	syms map[grammarKey]int
+}
+
+type grammarKey struct {
+	w   *Word
+	inv bool
+}
+
+func newGrammar() *grammar {
+	return &grammar{syms: make(map[grammarKey]int)}
+}
+
+// distinct letters for x and x^-1
+func letterCode(gen, exp int) int {
+	if exp < 0 {
+		return gen<<1 + 1
+	}
+	return gen << 1
+}
+
+// the symbol deriving the non-identity w, or its inverse if inv, with one rule per leaf and O(log exp) rules per product node
+func (g *grammar) symbol(w *Word, inv bool) int {
+	key := grammarKey{w, inv}
+	if s, ok := g.syms[key]; ok {
+		return s
+	}
+	var s int
+	if w.gen >= 0 {
+		exp := w.exp
+		if inv {
+			exp = -exp
+		}
+		s = g.Run(letterCode(w.gen, exp), abs(exp))
+	} else {
+		s = g.Pow(g.base(w, inv), w.exp)
+	}
+	g.syms[key] = s
+	return s
+}
+
+// the symbol deriving left right for a product node (left right)^exp, or (right^-1 left^-1) if inv
+func (g *grammar) base(w *Word, inv bool) int {
+	if inv {
+		return g.Concat(g.symbol(w.right, true), g.symbol(w.left, true))
+	}
+	return g.Concat(g.symbol(w.left, false), g.symbol(w.right, false))
+}
+
+// the symbol deriving w[i:j], or the same subword of the inverse of w if inv, for 0 <= i < j <= w.len, with O(depth) new rules
+// This follows (*Word).slice, without building new Words
+func (g *grammar) slice(w *Word, inv bool, i, j int) int {
+	switch {
+	case i == 0 && j == w.len:
+		return g.symbol(w, inv)
+	case w.gen >= 0:
+		return g.Run(letterCode(w.gen, w.exp*boolSign(inv)), j-i)
+	}
+	first, second := w.left, w.right
+	if inv {
+		first, second = w.right, w.left
+	}
+	period := first.len + second.len
+	once := func(i, j int) int {
+		l := first.len
+		switch {
+		case j <= l:
+			return g.slice(first, inv, i, j)
+		case i >= l:
+			return g.slice(second, inv, i-l, j-l)
+		}
+		return g.Concat(g.slice(first, inv, i, l), g.slice(second, inv, 0, j-l))
+	}
+	a, b := i/period, (j-1)/period
+	if a == b {
+		return once(i-a*period, j-a*period)
+	}
+	s := once(i-a*period, period)
+	if b-a > 1 {
+		s = g.Concat(s, g.Pow(g.base(w, inv), b-a-1))
+	}
+	return g.Concat(s, once(0, j-b*period))
+}
+
+// -1 if inv, 1 otherwise
+func boolSign(inv bool) int {
+	if inv {
+		return -1
+	}
+	return 1
+}
+
+// the length of the longest suffix of u whose inverse is a prefix of v, at most hi, for reduced u and v
+// The boundary syllables x^m and x^-n cancel min(m, n) letters, and if m != n that is all
+// Otherwise, since u and v are reduced, a suffix cancels exactly when all shorter ones do, so we binary search its length,
+// comparing the suffixes of u with the suffixes of the inverse of v by recompression
+func cancellation(u, v *Word, hi int) int {
+	if u.IsIdentity() || v.IsIdentity() || u.endGen != v.startGen || sign(u.endExp) == sign(v.startExp) {
+		return 0
+	}
+	lo := min(abs(u.endExp), abs(v.startExp), hi)
+	if abs(u.endExp) != abs(v.startExp) || lo == hi {
+		return lo
+	}
+	g := newGrammar()
+	for lo < hi {
+		k := (lo + hi + 1) / 2
+		if g.Equal(g.slice(u, false, u.len-k, u.len), g.slice(v, true, v.len-k, v.len)) {
+			lo = k
+		} else {
+			hi = k - 1
+		}
+	}
+	return lo
+}
+
+// checks if two Words are equal as elements of the free group
+// hash-consing makes this O(1) when both were built the same way
+// Otherwise the trees may bracket the same word differently, and we compare them by recompression,
+// deterministically and in time polynomial in the size of the trees and the log of their lengths
+func Equal(u, v *Word) bool {
+	switch {
+	case u == v:
+		return true
+	case u.len != v.len || u.startGen != v.startGen || u.startExp != v.startExp || u.endGen != v.endGen || u.endExp != v.endExp:
+		return false
+	}
+	g := newGrammar()
+	return g.Equal(g.symbol(u, false), g.symbol(v, false))
+}
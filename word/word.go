@@ -1,12 +1,18 @@
 package word
 
-import "sync"
+import (
+	"iter"
+	"runtime"
+	"sync"
+	"weak"
+)
 
 // We give each Word encoutered a unique pointer via this thread-safe map.
+// It holds weak pointers, and entries are removed once their Word is garbage collected, so it only grows with the Words in use.
 var WordCache sync.Map
 
 // Word represents an element of a free group using a tree-based structure.
-// This allows for compact representation of large powers and efficient 
+// This allows for compact representation of large powers and efficient
 // boundary reduction.
 type Word struct {
 	// gen determines the node type:
@@ -31,4 +37,313 @@ type Word struct {
 	startExp int
 	endGen   int
 	endExp   int
-}
\ No newline at end of file
+}
+
+// Words built with the constructors below are always freely reduced, and structurally equal Words are the same pointer (hash-consing)
+// Every syllable (maximal power of a single generator) of a Word is a single leaf, so the start/end metadata are whole syllables
+
+// key used for hash-consing in WordCache
+type nodeKey struct {
+	gen, exp    int
+	left, right *Word
+}
+
+// returns the unique pointer for the given node, computing its metadata on first encounter
+func intern(gen, exp int, left, right *Word) *Word {
+	key := nodeKey{gen, exp, left, right}
+	if p, ok := WordCache.Load(key); ok {
+		if w := p.(weak.Pointer[Word]).Value(); w != nil {
+			return w
+		}
+	}
+	w := &Word{gen: gen, exp: exp, left: left, right: right}
+	switch {
+	case gen >= 0:
+		w.len = abs(exp)
+		w.startGen, w.startExp, w.endGen, w.endExp = gen, exp, gen, exp
+	case gen == -1:
+		w.len = (left.len + right.len) * exp
+		w.startGen, w.startExp = left.startGen, left.startExp
+		w.endGen, w.endExp = right.endGen, right.endExp
+	default:
+		w.startGen, w.endGen = -1, -1 //no generator has index -1 so the identity never cancels with anything
+	}
+	p := weak.Make(w)
+	for {
+		actual, loaded := WordCache.LoadOrStore(key, p)
+		if !loaded {
+			break
+		}
+		if v := actual.(weak.Pointer[Word]).Value(); v != nil {
+			return v
+		}
+		if WordCache.CompareAndSwap(key, actual, p) { //the Word there was collected, but its cleanup hasn't run yet
+			break
+		}
+	}
+	runtime.AddCleanup(w, func(key nodeKey) { WordCache.CompareAndDelete(key, p) }, key)
+	return w
+}
+
+var identity = intern(-2, 0, nil, nil)
+
+// the empty word
+func Identity() *Word { return identity }
+
+// the word gen^exp, which is the identity if exp is 0
+func Letter(gen, exp int) *Word {
+	if exp == 0 {
+		return identity
+	}
+	return intern(gen, exp, nil, nil)
+}
+
+func (w *Word) IsIdentity() bool { return w.gen == -2 }
+
+// Gives the true length of a word (sum of absolute exponents of all generators) in O(1).
+func (w *Word) Len() int { return w.len }
+
+// concatenation of two words when we already know nothing cancels at the boundary
+// powers of the same base are merged, e.g. (ab)^2(ab)^3 = (ab)^5
+func join(u, v *Word) *Word {
+	switch {
+	case u.IsIdentity():
+		return v
+	case v.IsIdentity():
+		return u
+	case u.gen == -1 && v.gen == -1 && u.left == v.left && u.right == v.right:
+		return intern(-1, u.exp+v.exp, u.left, u.right)
+	}
+	return intern(-1, 1, u, v)
+}
+
+// splits off the first syllable of a non-identity word, O(depth) new nodes
+func splitFirst(w *Word) (*Word, *Word) {
+	if w.gen >= 0 {
+		return w, identity
+	}
+	first, rest := splitFirst(w.left)
+	rest = join(rest, w.right)
+	if w.exp > 1 {
+		rest = join(rest, intern(-1, w.exp-1, w.left, w.right)) //right ends with a different generator than left starts with, so no cancellation
+	}
+	return first, rest
+}
+
+// splits off the last syllable of a non-identity word, O(depth) new nodes
+func splitLast(w *Word) (*Word, *Word) {
+	if w.gen >= 0 {
+		return identity, w
+	}
+	rest, last := splitLast(w.right)
+	rest = join(w.left, rest)
+	if w.exp > 1 {
+		rest = join(intern(-1, w.exp-1, w.left, w.right), rest)
+	}
+	return rest, last
+}
+
+// Concat returns the freely reduced product uv
+// Checking for cancellation is O(1) thanks to the boundary metadata, and so is the cancellation itself when the boundary syllables
+// x^m x^-n have m != n. Otherwise we find how far it goes with compressed equality checks (see cancellation), so e.g. (ab)^n (b^-1a^-1)^n
+// cancels in time polynomial in the size of the trees and log n, however the powers are bracketed
+func Concat(u, v *Word) *Word {
+	switch {
+	case u.IsIdentity():
+		return v
+	case v.IsIdentity():
+		return u
+	case u.endGen != v.startGen:
+		return join(u, v)
+	case sign(u.endExp) != sign(v.startExp):
+		k := cancellation(u, v, min(u.len, v.len))
+		u, v = u.slice(0, u.len-k), v.slice(k, v.len)
+		if u.IsIdentity() || v.IsIdentity() || u.endGen != v.startGen {
+			return join(u, v)
+		}
+	}
+	// x^e and x^f with e, f of the same sign merge into a single syllable
+	rest, last := splitLast(u)
+	first, tail := splitFirst(v)
+	return join(join(rest, Letter(last.gen, last.exp+first.exp)), tail)
+}
+
+// Inv returns the inverse of w, with one new node per node of the tree
+func Inv(w *Word) *Word {
+	return inv(w, make(map[*Word]*Word))
+}
+
+func inv(w *Word, memo map[*Word]*Word) *Word {
+	if w.gen != -1 {
+		return Letter(w.gen, -w.exp) //also fine for the identity
+	}
+	if i, ok := memo[w]; ok {
+		return i
+	}
+	i := intern(-1, w.exp, inv(w.right, memo), inv(w.left, memo)) //(lr)^k inverts to (r^-1l^-1)^k
+	memo[w] = i
+	return i
+}
+
+// Pow returns w^n, stored as a single exponent node whenever w is cyclically reduced
+// Otherwise w = c r c^-1 with r cyclically reduced, and we return c r^n c^-1, finding c as the cancellation between w and itself
+func Pow(w *Word, n int) *Word {
+	switch {
+	case n == 0 || w.IsIdentity():
+		return identity
+	case n < 0:
+		return Pow(Inv(w), -n)
+	case n == 1:
+		return w
+	case w.gen >= 0:
+		return Letter(w.gen, w.exp*n)
+	case w.startGen != w.endGen:
+		return intern(-1, w.exp*n, w.left, w.right)
+	}
+	if k := cancellation(w, w, (w.len-1)/2); k > 0 {
+		c := w.slice(0, k)
+		return Concat(Concat(c, Pow(w.slice(k, w.len-k), n)), Inv(c))
+	}
+	// w = x^e m x^f with e, f of the same sign, so w^n = x^e (m x^(e+f))^n x^-e where m x^(e+f) is cyclically reduced
+	first, rest := splitFirst(w)
+	return Concat(Concat(first, Pow(Concat(rest, first), n)), Inv(first))
+}
+
+// Returns the letter at index i as a generator exponent pair (exponent ±1) in O(depth) time
+// Careful! This function panics on an invalid index.
+func (w *Word) At(i int) [2]int {
+	if i < 0 || i >= w.len {
+		panic("invalid index")
+	}
+	for w.gen == -1 {
+		i %= w.left.len + w.right.len
+		if i < w.left.len {
+			w = w.left
+		} else {
+			i -= w.left.len
+			w = w.right
+		}
+	}
+	return [2]int{w.gen, sign(w.exp)}
+}
+
+// Returns the subword of w from index i to j, excluding j, with O(depth) new nodes
+// Careful! This function panics on invalid indices.
+func (w *Word) Slice(i, j int) *Word {
+	if i < 0 || j < i || j > w.len {
+		panic("invalid index")
+	}
+	return w.slice(i, j)
+}
+
+// the subword of w from index i to j, excluding j, with O(depth) new nodes, for 0 <= i <= j <= w.len
+func (w *Word) slice(i, j int) *Word {
+	switch {
+	case i == j:
+		return identity
+	case i == 0 && j == w.len:
+		return w
+	case w.gen >= 0:
+		return Letter(w.gen, sign(w.exp)*(j-i))
+	}
+	// w = (lr)^k, we slice the copies of lr that i and j fall into and keep the full copies in between as a power
+	period := w.left.len + w.right.len
+	once := func(i, j int) *Word {
+		l := w.left.len
+		switch {
+		case j <= l:
+			return w.left.slice(i, j)
+		case i >= l:
+			return w.right.slice(i-l, j-l)
+		}
+		return join(w.left.slice(i, l), w.right.slice(0, j-l))
+	}
+	a, b := i/period, (j-1)/period
+	if a == b {
+		return once(i-a*period, j-a*period)
+	}
+	sliced := once(i-a*period, period)
+	if b-a > 1 {
+		sliced = join(sliced, intern(-1, b-a-1, w.left, w.right))
+	}
+	return join(sliced, once(0, j-b*period)) //subwords of reduced words are reduced so nothing cancels
+}
+
+// Syllables iterates over the syllables (generator exponent pairs) of w in order
+// Powers are unrolled lazily, so this takes time proportional to the number of syllables but constant memory per tree level
+func (w *Word) Syllables() iter.Seq[[2]int] {
+	return func(yield func([2]int) bool) {
+		var visit func(*Word) bool
+		visit = func(x *Word) bool {
+			switch {
+			case x.gen >= 0:
+				return yield([2]int{x.gen, x.exp})
+			case x.gen == -1:
+				for range x.exp {
+					if !visit(x.left) || !visit(x.right) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		visit(w)
+	}
+}
+
+// Builds a Word from generator exponent pairs, freely reducing along the way
+func FromRaw(w [][2]int) *Word {
+	switch len(w) {
+	case 0:
+		return identity
+	case 1:
+		return Letter(w[0][0], w[0][1])
+	}
+	mid := len(w) / 2
+	return Concat(FromRaw(w[:mid]), FromRaw(w[mid:]))
+}
+
+// Expands w into generator exponent pairs
+// WARNING: this materializes every syllable of every power, only use it on words with a reasonable number of syllables
+func (w *Word) Raw() [][2]int {
+	raw := make([][2]int, 0)
+	for s := range w.Syllables() {
+		raw = append(raw, s)
+	}
+	return raw
+}
+
+// ExponentSums returns the exponent sum of each generator appearing in w, without expanding powers
+func (w *Word) ExponentSums() map[int]int {
+	memo := make(map[*Word]map[int]int)
+	var sums func(*Word) map[int]int
+	sums = func(x *Word) map[int]int {
+		if s, ok := memo[x]; ok {
+			return s
+		}
+		s := make(map[int]int)
+		switch {
+		case x.gen >= 0:
+			s[x.gen] = x.exp
+		case x.gen == -1:
+			for g, e := range sums(x.left) {
+				s[g] += e * x.exp
+			}
+			for g, e := range sums(x.right) {
+				s[g] += e * x.exp
+			}
+		}
+		memo[x] = s
+		return s
+	}
+	return sums(w)
+}
+
+// find highest generator index in w, -1 for the identity
+func (w *Word) MaxGen() int {
+	gens := -1
+	for g := range w.ExponentSums() {
+		gens = max(gens, g)
+	}
+	return gens
+}
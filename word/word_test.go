@@ -0,0 +1,142 @@
+package word_test
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/geometricgrouptheorydev/groups-in-go/word"
+)
+
+// free reduction of generator exponent pairs, to check the trees against
+func reduce(w [][2]int) [][2]int {
+	r := make([][2]int, 0, len(w))
+	for _, s := range w {
+		switch {
+		case s[1] == 0:
+		case len(r) > 0 && r[len(r)-1][0] == s[0]:
+			if r[len(r)-1][1] += s[1]; r[len(r)-1][1] == 0 {
+				r = r[:len(r)-1]
+			}
+		default:
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+func inverse(w [][2]int) [][2]int {
+	v := make([][2]int, 0, len(w))
+	for i := len(w) - 1; i >= 0; i-- {
+		v = append(v, [2]int{w[i][0], -w[i][1]})
+	}
+	return v
+}
+
+func TestSmallWords(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	random := func() [][2]int {
+		w := make([][2]int, rng.IntN(6))
+		for i := range w {
+			w[i] = [2]int{rng.IntN(3), rng.IntN(5) - 2}
+		}
+		return w
+	}
+	for range 500 {
+		u, v := random(), random()
+		n := rng.IntN(5) + 1
+		tu, tv := word.FromRaw(u), word.FromRaw(v)
+		pow := make([][2]int, 0)
+		for range n {
+			pow = append(pow, u...)
+		}
+		checks := []struct {
+			name string
+			got  *word.Word
+			want [][2]int
+		}{
+			{"Concat", word.Concat(tu, tv), reduce(append(slices.Clone(u), v...))},
+			{"Inv", word.Inv(tu), reduce(inverse(u))},
+			{"Pow", word.Pow(tu, n), reduce(pow)},
+			{"Pow with negative exponent", word.Pow(tu, -n), reduce(inverse(pow))},
+			{"Concat of powers", word.Concat(word.Pow(tu, n+2), word.Concat(word.Pow(tu, -n), tv)), reduce(append(append(slices.Clone(u), u...), v...))},
+		}
+		for _, c := range checks {
+			if got := c.got.Raw(); !slices.Equal(got, c.want) {
+				t.Fatalf("%v on %v, %v (n = %v) = %v, want %v", c.name, u, v, n, got, c.want)
+			}
+			if !word.Equal(c.got, word.FromRaw(c.want)) {
+				t.Fatalf("%v on %v, %v is not Equal to FromRaw(%v)", c.name, u, v, c.want)
+			}
+		}
+		if word.Equal(tu, tv) != slices.Equal(reduce(u), reduce(v)) {
+			t.Fatalf("Equal(%v, %v) = %v", u, v, word.Equal(tu, tv))
+		}
+	}
+}
+
+// powers cancel and compare without being expanded, so these would take hours syllable by syllable
+func TestLargeExponents(t *testing.T) {
+	const n = 1000000000
+	ab, c := word.FromRaw([][2]int{{0, 1}, {1, 1}}), word.Letter(2, 1)
+	a, b, ba := word.Letter(0, 1), word.Letter(1, 1), word.FromRaw([][2]int{{1, 1}, {0, 1}})
+	tests := []struct {
+		name string
+		got  *word.Word
+		want *word.Word
+	}{
+		{"power against power", word.Concat(word.Pow(ab, n), word.Pow(ab, -n+1)), ab},
+		{"power against nested power", word.Concat(word.Pow(ab, n), word.Concat(word.Pow(ab, -n), c)), c},
+		{"nested power against power", word.Concat(word.Concat(c, word.Pow(ab, n)), word.Pow(ab, 1-n)), word.Concat(c, ab)},
+		{"inverse", word.Concat(word.Pow(word.Concat(ab, c), n), word.Inv(word.Pow(word.Concat(ab, c), n))), word.Identity()},
+		{"powers split differently", word.Concat(word.Concat(c, word.Pow(ab, n/2)), word.Pow(ab, n/2)), word.Concat(c, word.Pow(ab, n))},
+		{"powers of a power", word.Pow(word.Pow(ab, 1000), n/1000), word.Pow(ab, n)},
+		{"bracketed differently", word.Concat(word.Concat(a, word.Pow(ba, n-1)), b), word.Pow(ab, n)},
+		{"cancel bracketed differently", word.Concat(word.Concat(word.Concat(a, word.Pow(ba, n-1)), b), word.Concat(word.Pow(ab, 1-n), c)), word.Concat(ab, c)},
+		{"power of a conjugate", word.Pow(word.Concat(word.Pow(ab, n), word.Concat(c, word.Pow(ab, -n))), 3), word.Concat(word.Pow(ab, n), word.Concat(word.Letter(2, 3), word.Pow(ab, -n)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !word.Equal(tt.got, tt.want) || tt.got.Len() != tt.want.Len() {
+				t.Fatalf("got a word of length %v, want %v", tt.got.Len(), tt.want.Len())
+			}
+		})
+	}
+
+	long := word.Concat(c, word.Pow(ab, n))
+	if long.Len() != 2*n+1 || long.At(2*n) != [2]int{1, 1} || long.At(1) != [2]int{0, 1} {
+		t.Errorf("c(ab)^n has length %v and letters %v, %v", long.Len(), long.At(1), long.At(2*n))
+	}
+	if word.Equal(long, word.Concat(c, word.Pow(ab, n-1))) || word.Equal(long, word.Concat(word.Pow(ab, n), c)) || word.Equal(word.Pow(ab, n), word.Concat(word.Concat(b, word.Pow(ab, n-1)), a)) {
+		t.Errorf("different words are Equal")
+	}
+}
+
+// the cache only holds weak pointers, so words nobody uses anymore leave it
+func TestWordCacheShrinks(t *testing.T) {
+	entries := func() int {
+		n := 0
+		word.WordCache.Range(func(_, _ any) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	before := entries()
+	for i := range 10000 {
+		word.Concat(word.Letter(0, i+1), word.Letter(1, 1))
+	}
+	if entries() < before+10000 {
+		t.Fatalf("%v entries after adding 10000 words to %v", entries(), before)
+	}
+	for range 100 {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond) //cleanups run in their own goroutine
+		if entries() < before+100 {
+			return
+		}
+	}
+	t.Errorf("%v entries are left after garbage collection, from %v", entries(), before)
+}